@@ -0,0 +1,227 @@
+package dynamis
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// maxBatchWrite is the most requests DynamoDB takes in one BatchWriteItem.
+const maxBatchWrite = 25
+
+// Default retry settings for batch operations.
+const (
+	DefaultBatchRetries   = 8
+	DefaultBatchBaseDelay = 50 * time.Millisecond
+	DefaultBatchMaxDelay  = 5 * time.Second
+)
+
+// BatchWriter puts and deletes items in a table with BatchWriteItem. It
+// collects writes and sends them in groups of 25. DynamoDB may leave some
+// writes of a group unprocessed when it is busy, and those are retried with
+// jittered exponential backoff until MaxRetries is reached.
+//
+// DynamoDB rejects a group that writes the same key twice, so a write to a
+// key that is already in the pending group replaces the earlier write, as if
+// they had been sent one after the other. To find the key of a put, the
+// writer reads the table's key schema with DescribeTable on the first Put.
+//
+// Writes are sent as they fill a group, so call Flush when done to send the
+// rest and find out which writes failed. A BatchWriter is not safe for
+// concurrent use.
+type BatchWriter struct {
+	// MaxRetries is the number of times to retry unprocessed writes.
+	MaxRetries int
+
+	// BaseDelay is the longest wait before the first retry. Each retry
+	// waits a random time up to twice as long as the one before, up to
+	// MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+
+	table    Table
+	keyNames []string
+	pending  []*dynamodb.WriteRequest
+	byKey    map[string]int
+	failed   []*dynamodb.WriteRequest
+	err      error
+
+	// describe, write and sleep are replaced in tests.
+	describe func(*dynamodb.DescribeTableInput) (*dynamodb.DescribeTableOutput, error)
+	write    func(*dynamodb.BatchWriteItemInput) (*dynamodb.BatchWriteItemOutput, error)
+	sleep    func(time.Duration)
+}
+
+// BatchWriter initializes a batch writer over the table.
+func (t Table) BatchWriter() *BatchWriter {
+	return &BatchWriter{
+		MaxRetries: DefaultBatchRetries,
+		BaseDelay:  DefaultBatchBaseDelay,
+		MaxDelay:   DefaultBatchMaxDelay,
+		table:      t,
+		describe:   t.db.DescribeTable,
+		write:      t.db.BatchWriteItem,
+		sleep:      time.Sleep,
+	}
+}
+
+// BatchWriteError reports the writes that failed, either because they were
+// still unprocessed when the retries ran out, or because DynamoDB rejected
+// the request that held them.
+type BatchWriteError struct {
+	// Requests are the writes that failed.
+	Requests []*dynamodb.WriteRequest
+
+	// Err is the last error from DynamoDB, or nil if the writes failed only
+	// because they were unprocessed.
+	Err error
+}
+
+func (e *BatchWriteError) Error() string {
+	msg := fmt.Sprintf("dynamis: %d batch writes failed", len(e.Requests))
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the last error from DynamoDB.
+func (e *BatchWriteError) Unwrap() error {
+	return e.Err
+}
+
+// Put adds a write of the item built by the function. It returns ErrEmptyItem
+// if the function writes nothing, or the error from reading the key schema.
+func (w *BatchWriter) Put(item func(ValueWriter)) error {
+	i, err := buildItem(item)
+	if err != nil {
+		return err
+	}
+	names, err := w.keys()
+	if err != nil {
+		return err
+	}
+	k := make(map[string]*dynamodb.AttributeValue, len(names))
+	for _, name := range names {
+		if val, ok := i[name]; ok {
+			k[name] = val
+		}
+	}
+	w.add(k, &dynamodb.WriteRequest{
+		PutRequest: &dynamodb.PutRequest{Item: i},
+	})
+	return nil
+}
+
+// Delete adds a delete of the item with the key written by the function.
+func (w *BatchWriter) Delete(key func(ValueWriter)) error {
+	k, err := buildKey(key)
+	if err != nil {
+		return err
+	}
+	w.add(k, &dynamodb.WriteRequest{
+		DeleteRequest: &dynamodb.DeleteRequest{Key: k},
+	})
+	return nil
+}
+
+// keys returns the names of the table's key attributes, reading them once.
+func (w *BatchWriter) keys() ([]string, error) {
+	if w.keyNames != nil {
+		return w.keyNames, nil
+	}
+	resp, err := w.describe(&dynamodb.DescribeTableInput{
+		TableName: aws.String(w.table.tableName),
+	})
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, 2)
+	for _, k := range resp.Table.KeySchema {
+		names = append(names, aws.StringValue(k.AttributeName))
+	}
+	w.keyNames = names
+	return names, nil
+}
+
+// add adds a write of the key to the pending group, replacing any earlier
+// write of the same key, and sends the group when it is full.
+func (w *BatchWriter) add(key map[string]*dynamodb.AttributeValue, req *dynamodb.WriteRequest) {
+	id := keyString(key)
+	if i, ok := w.byKey[id]; ok {
+		w.pending[i] = req
+		return
+	}
+	if w.byKey == nil {
+		w.byKey = make(map[string]int)
+	}
+	w.byKey[id] = len(w.pending)
+	w.pending = append(w.pending, req)
+	if len(w.pending) == maxBatchWrite {
+		w.sendPending()
+	}
+}
+
+// sendPending sends the pending group and starts a new one.
+func (w *BatchWriter) sendPending() {
+	w.send(w.pending)
+	w.pending, w.byKey = nil, nil
+}
+
+// Flush sends any pending writes. If any write failed since the last Flush, it
+// returns a *BatchWriteError holding them.
+func (w *BatchWriter) Flush() error {
+	if len(w.pending) > 0 {
+		w.sendPending()
+	}
+	if len(w.failed) == 0 {
+		return nil
+	}
+	err := &BatchWriteError{w.failed, w.err}
+	w.failed, w.err = nil, nil
+	return err
+}
+
+// send writes a group, retrying unprocessed writes, and records any that
+// fail.
+func (w *BatchWriter) send(reqs []*dynamodb.WriteRequest) {
+	for attempt := 0; len(reqs) > 0; attempt++ {
+		if attempt > w.MaxRetries {
+			w.failed = append(w.failed, reqs...)
+			return
+		}
+		if attempt > 0 {
+			w.sleep(backoff(w.BaseDelay, w.MaxDelay, attempt-1))
+		}
+		resp, err := w.write(&dynamodb.BatchWriteItemInput{
+			RequestItems: map[string][]*dynamodb.WriteRequest{
+				w.table.tableName: reqs,
+			},
+		})
+		if err != nil {
+			w.failed = append(w.failed, reqs...)
+			w.err = err
+			return
+		}
+		reqs = resp.UnprocessedItems[w.table.tableName]
+	}
+}
+
+// backoff returns a random delay up to base doubled for each retry, capped at
+// max.
+func backoff(base, max time.Duration, retry int) time.Duration {
+	d := base
+	for i := 0; i < retry && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+	if d <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(d)) + 1)
+}
@@ -0,0 +1,289 @@
+package dynamis
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		base, max time.Duration
+		retry     int
+		limit     time.Duration
+	}{
+		{base: 10, max: 1000, retry: 0, limit: 10},
+		{base: 10, max: 1000, retry: 1, limit: 20},
+		{base: 10, max: 1000, retry: 3, limit: 80},
+		{base: 10, max: 1000, retry: 20, limit: 1000},
+		{base: 10, max: 5, retry: 0, limit: 5},
+		{base: 0, max: 1000, retry: 3, limit: 0},
+	}
+	for i, test := range tests {
+		for n := 0; n < 20; n++ {
+			got := backoff(test.base, test.max, test.retry)
+			if got < 0 || got > test.limit || (test.limit > 0 && got == 0) {
+				t.Errorf("%d backoff() got %d, want (0, %d]", i, got, test.limit)
+				break
+			}
+		}
+	}
+}
+
+// fakeBatchWrite records BatchWriteItem calls, and leaves the last
+// unprocessed writes of each call unprocessed.
+type fakeBatchWrite struct {
+	calls       [][]*dynamodb.WriteRequest
+	unprocessed int
+	err         error
+	describes   int
+	describeErr error
+}
+
+func (f *fakeBatchWrite) write(input *dynamodb.BatchWriteItemInput) (*dynamodb.BatchWriteItemOutput, error) {
+	reqs := input.RequestItems["users"]
+	f.calls = append(f.calls, reqs)
+	if f.err != nil {
+		return nil, f.err
+	}
+	out := &dynamodb.BatchWriteItemOutput{}
+	if n := f.unprocessed; n > 0 {
+		if n > len(reqs) {
+			n = len(reqs)
+		}
+		out.UnprocessedItems = map[string][]*dynamodb.WriteRequest{
+			"users": reqs[len(reqs)-n:],
+		}
+	}
+	return out, nil
+}
+
+// describe returns a key schema with the partition key "id".
+func (f *fakeBatchWrite) describe(input *dynamodb.DescribeTableInput) (*dynamodb.DescribeTableOutput, error) {
+	f.describes++
+	if f.describeErr != nil {
+		return nil, f.describeErr
+	}
+	return &dynamodb.DescribeTableOutput{Table: &dynamodb.TableDescription{
+		TableName: input.TableName,
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{AttributeName: aws.String("id"), KeyType: aws.String("HASH")},
+		},
+	}}, nil
+}
+
+func newFakeBatchWriter(f *fakeBatchWrite) (*BatchWriter, *[]time.Duration) {
+	var sleeps []time.Duration
+	w := Table{tableName: "users"}.BatchWriter()
+	w.describe = f.describe
+	w.write = f.write
+	w.sleep = func(d time.Duration) { sleeps = append(sleeps, d) }
+	return w, &sleeps
+}
+
+func putN(w *BatchWriter, n int) error {
+	for i := 0; i < n; i++ {
+		err := w.Put(func(w ValueWriter) {
+			w.Int("id", i)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestBatchWriterGroups(t *testing.T) {
+	f := &fakeBatchWrite{}
+	w, sleeps := newFakeBatchWriter(f)
+	if err := putN(w, 60); err != nil {
+		t.Fatalf("Put() error %s", err)
+	}
+	// Full groups are sent as they fill.
+	if got, want := len(f.calls), 2; got != want {
+		t.Errorf("calls before Flush() got %d, want %d", got, want)
+	}
+	err := w.Delete(func(w ValueWriter) {
+		w.Int("id", 0)
+	})
+	if err != nil {
+		t.Fatalf("Delete() error %s", err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush() error %s", err)
+	}
+	var sizes []int
+	for _, call := range f.calls {
+		sizes = append(sizes, len(call))
+	}
+	if len(sizes) != 3 || sizes[0] != 25 || sizes[1] != 25 || sizes[2] != 11 {
+		t.Errorf("group sizes got %v, want [25 25 11]", sizes)
+	}
+	if last := f.calls[2][10]; last.DeleteRequest == nil || Int(last.DeleteRequest.Key, "id") != 0 {
+		t.Errorf("last write got %#v", last)
+	}
+	if len(*sleeps) != 0 {
+		t.Errorf("sleeps got %v, want none", *sleeps)
+	}
+
+	// Nothing left to send.
+	if err := w.Flush(); err != nil || len(f.calls) != 3 {
+		t.Errorf("Flush() again got (%d calls, %v)", len(f.calls), err)
+	}
+
+	// Empty items are rejected.
+	if err := w.Put(func(w ValueWriter) { w.Str("id", "") }); err != ErrEmptyItem {
+		t.Errorf("Put(empty) got %v, want %v", err, ErrEmptyItem)
+	}
+	if err := w.Delete(func(w ValueWriter) { w.Str("id", "") }); err != ErrEmptyKey {
+		t.Errorf("Delete(empty) got %v, want %v", err, ErrEmptyKey)
+	}
+}
+
+func TestBatchWriterDuplicates(t *testing.T) {
+	f := &fakeBatchWrite{}
+	w, _ := newFakeBatchWriter(f)
+	put := func(id int, name string) {
+		err := w.Put(func(w ValueWriter) {
+			w.Int("id", id)
+			w.Str("name", name)
+		})
+		if err != nil {
+			t.Fatalf("Put() error %s", err)
+		}
+	}
+	put(1, "a")
+	put(2, "b")
+	put(1, "c")
+	if err := w.Delete(func(w ValueWriter) { w.Int("id", 2) }); err != nil {
+		t.Fatalf("Delete() error %s", err)
+	}
+	// A full group of other keys, so the next write of 1 starts a new group.
+	for i := 0; i < maxBatchWrite-2; i++ {
+		put(100+i, "x")
+	}
+	put(1, "d")
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush() error %s", err)
+	}
+	if got, want := len(f.calls), 2; got != want {
+		t.Fatalf("calls got %d, want %d", got, want)
+	}
+	// Later writes replace earlier writes of the same key in the group.
+	first := f.calls[0]
+	if len(first) != maxBatchWrite {
+		t.Errorf("first group size got %d, want %d", len(first), maxBatchWrite)
+	}
+	if r := first[0].PutRequest; r == nil || Str(r.Item, "name") != "c" {
+		t.Errorf("first write got %#v, want put of c", first[0])
+	}
+	if r := first[1].DeleteRequest; r == nil || Int(r.Key, "id") != 2 {
+		t.Errorf("second write got %#v, want delete of 2", first[1])
+	}
+	if second := f.calls[1]; len(second) != 1 || Str(second[0].PutRequest.Item, "name") != "d" {
+		t.Errorf("second group got %#v", second)
+	}
+	// The key schema is read once.
+	if f.describes != 1 {
+		t.Errorf("describes got %d, want 1", f.describes)
+	}
+
+	// Puts fail if the key schema can't be read.
+	f = &fakeBatchWrite{describeErr: errors.New("no table")}
+	w, _ = newFakeBatchWriter(f)
+	if err := w.Put(func(w ValueWriter) { w.Int("id", 1) }); err != f.describeErr {
+		t.Errorf("Put() got %v, want %v", err, f.describeErr)
+	}
+}
+
+func TestBatchWriterRetry(t *testing.T) {
+	f := &fakeBatchWrite{unprocessed: 2}
+	w, sleeps := newFakeBatchWriter(f)
+	w.MaxRetries = 3
+	if err := putN(w, 5); err != nil {
+		t.Fatalf("Put() error %s", err)
+	}
+	err := w.Flush()
+	berr, ok := err.(*BatchWriteError)
+	if !ok {
+		t.Fatalf("Flush() got %#v, want *BatchWriteError", err)
+	}
+	if got, want := len(berr.Requests), 2; got != want || berr.Err != nil {
+		t.Errorf("BatchWriteError got (%d, %v), want %d", got, berr.Err, want)
+	}
+	// One call and three retries of the unprocessed writes.
+	if got, want := len(f.calls), 4; got != want {
+		t.Errorf("calls got %d, want %d", got, want)
+	}
+	for i, call := range f.calls[1:] {
+		if len(call) != 2 {
+			t.Errorf("retry %d got %d writes, want 2", i, len(call))
+		}
+	}
+	if got, want := len(*sleeps), 3; got != want {
+		t.Errorf("sleeps got %d, want %d", got, want)
+	}
+	for i, d := range *sleeps {
+		if limit := w.BaseDelay << uint(i); d <= 0 || d > limit {
+			t.Errorf("sleep %d got %s, want (0, %s]", i, d, limit)
+		}
+	}
+
+	// Failures are reported once.
+	if err := w.Flush(); err != nil {
+		t.Errorf("Flush() again got %v", err)
+	}
+}
+
+func TestBatchWriterError(t *testing.T) {
+	failure := errors.New("boom")
+	f := &fakeBatchWrite{err: failure}
+	w, _ := newFakeBatchWriter(f)
+	if err := putN(w, 30); err != nil {
+		t.Fatalf("Put() error %s", err)
+	}
+	err := w.Flush()
+	berr, ok := err.(*BatchWriteError)
+	if !ok || len(berr.Requests) != 30 || berr.Unwrap() != failure {
+		t.Errorf("Flush() got %#v", err)
+	}
+	if got, want := berr.Error(), "dynamis: 30 batch writes failed: boom"; got != want {
+		t.Errorf("Error() got %q, want %q", got, want)
+	}
+	if got, want := len(f.calls), 2; got != want {
+		t.Errorf("calls got %d, want %d", got, want)
+	}
+}
+
+func TestTableBatchWriter(t *testing.T) {
+	tbl := newTable()
+	if err := createStrTable(tbl, "old"); err != nil {
+		t.Fatalf("Failed initializing: %s", err)
+	}
+	table := CheckTable(tbl.db, tbl.name)
+	w := table.BatchWriter()
+	for i := 0; i < 30; i++ {
+		err := w.Put(func(w ValueWriter) {
+			w.Str("str", "k"+strconv.Itoa(i))
+		})
+		if err != nil {
+			t.Fatalf("Put() error %s", err)
+		}
+	}
+	err := w.Delete(func(w ValueWriter) {
+		w.Str("str", "old")
+	})
+	if err != nil {
+		t.Fatalf("Delete() error %s", err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush() error %s", err)
+	}
+	if got, want := table.RowCount(), 30; got != want {
+		t.Errorf("RowCount() got %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,297 @@
+package dynamis
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// maxBatchGet is the most keys DynamoDB takes in one BatchGetItem.
+const maxBatchGet = 100
+
+// BatchGetter reads items from one or more tables with BatchGetItem. Add keys
+// with Get, then call Run to read them. Keys are deduplicated and sent in
+// groups of 100, and keys DynamoDB leaves unprocessed are retried with
+// jittered exponential backoff until MaxRetries is reached.
+type BatchGetter struct {
+	// MaxRetries is the number of times to retry unprocessed keys.
+	MaxRetries int
+
+	// BaseDelay and MaxDelay bound the wait before each retry, as in
+	// BatchWriter.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+
+	tables []string
+	keys   map[string][]map[string]*dynamodb.AttributeValue
+	seen   map[string]bool
+
+	// get and sleep are replaced in tests.
+	get   func(*dynamodb.BatchGetItemInput) (*dynamodb.BatchGetItemOutput, error)
+	sleep func(time.Duration)
+}
+
+// NewBatchGetter initializes a batch getter.
+func NewBatchGetter(db *dynamodb.DynamoDB) *BatchGetter {
+	return &BatchGetter{
+		MaxRetries: DefaultBatchRetries,
+		BaseDelay:  DefaultBatchBaseDelay,
+		MaxDelay:   DefaultBatchMaxDelay,
+		keys:       make(map[string][]map[string]*dynamodb.AttributeValue),
+		seen:       make(map[string]bool),
+		get:        db.BatchGetItem,
+		sleep:      time.Sleep,
+	}
+}
+
+// Get adds the key written by the function to the keys read from the table.
+// Adding the same key twice reads it once.
+func (g *BatchGetter) Get(tableName string, key func(ValueWriter)) error {
+	k, err := buildKey(key)
+	if err != nil {
+		return err
+	}
+	id := tableName + "\x00" + keyString(k)
+	if g.seen[id] {
+		return nil
+	}
+	g.seen[id] = true
+	if _, ok := g.keys[tableName]; !ok {
+		g.tables = append(g.tables, tableName)
+	}
+	g.keys[tableName] = append(g.keys[tableName], k)
+	return nil
+}
+
+// Run reads every key. It always returns a result holding the items that were
+// read. If any keys could not be read, it also returns a *BatchGetError
+// holding them.
+func (g *BatchGetter) Run() (*BatchGetResult, error) {
+	keys := make(map[string][]map[string]*dynamodb.AttributeValue, len(g.keys))
+	for table, ks := range g.keys {
+		keys[table] = append([]map[string]*dynamodb.AttributeValue(nil), ks...)
+	}
+	r := &BatchGetResult{
+		keys:  keys,
+		items: make(map[string]map[string]map[string]*dynamodb.AttributeValue),
+		defs:  make(map[string]valueDefiner),
+	}
+	for _, table := range g.tables {
+		r.items[table] = make(map[string]map[string]*dynamodb.AttributeValue)
+		r.defs[table] = newValueDefiner()
+	}
+	var failed *BatchGetError
+	for _, group := range g.groups() {
+		if err := g.send(group, r); err != nil {
+			if failed == nil {
+				failed = &BatchGetError{Keys: make(map[string][]map[string]*dynamodb.AttributeValue)}
+			}
+			for table, ka := range err.Keys {
+				failed.Keys[table] = append(failed.Keys[table], ka...)
+			}
+			if err.Err != nil {
+				failed.Err = err.Err
+			}
+		}
+	}
+	if failed != nil {
+		r.failed = failed.Keys
+		return r, failed
+	}
+	return r, nil
+}
+
+// groups splits the keys into requests of up to 100 keys.
+func (g *BatchGetter) groups() []map[string]*dynamodb.KeysAndAttributes {
+	var (
+		groups []map[string]*dynamodb.KeysAndAttributes
+		group  map[string]*dynamodb.KeysAndAttributes
+		n      int
+	)
+	for _, table := range g.tables {
+		for _, k := range g.keys[table] {
+			if n%maxBatchGet == 0 {
+				group = make(map[string]*dynamodb.KeysAndAttributes)
+				groups = append(groups, group)
+			}
+			if group[table] == nil {
+				group[table] = &dynamodb.KeysAndAttributes{}
+			}
+			group[table].Keys = append(group[table].Keys, k)
+			n++
+		}
+	}
+	return groups
+}
+
+// send reads a group, retrying unprocessed keys, and stores the items in the
+// result. It returns the keys that could not be read.
+func (g *BatchGetter) send(group map[string]*dynamodb.KeysAndAttributes, r *BatchGetResult) *BatchGetError {
+	for attempt := 0; len(group) > 0; attempt++ {
+		if attempt > g.MaxRetries {
+			return newBatchGetError(group, nil)
+		}
+		if attempt > 0 {
+			g.sleep(backoff(g.BaseDelay, g.MaxDelay, attempt-1))
+		}
+		resp, err := g.get(&dynamodb.BatchGetItemInput{
+			RequestItems: group,
+		})
+		if err != nil {
+			return newBatchGetError(group, err)
+		}
+		for table, items := range resp.Responses {
+			for _, item := range items {
+				r.add(table, item)
+			}
+		}
+		group = resp.UnprocessedKeys
+	}
+	return nil
+}
+
+// BatchGetResult holds the items read by a BatchGetter.
+type BatchGetResult struct {
+	keys   map[string][]map[string]*dynamodb.AttributeValue
+	items  map[string]map[string]map[string]*dynamodb.AttributeValue
+	defs   map[string]valueDefiner
+	failed map[string][]map[string]*dynamodb.AttributeValue
+}
+
+// add stores an item under each of the table's keys that it matches.
+func (r *BatchGetResult) add(table string, item map[string]*dynamodb.AttributeValue) {
+	keys := r.keys[table]
+	if len(keys) == 0 {
+		return
+	}
+	// Every key of a table has the same attributes, so the first one says
+	// which attributes of the item make up its key.
+	k := make(map[string]*dynamodb.AttributeValue)
+	for name := range keys[0] {
+		k[name] = item[name]
+	}
+	r.items[table][keyString(k)] = item
+}
+
+// Rows returns a Row accessor for each item read from the table, in the order
+// their keys were added. The rows share the returned ValueDefiner, as with
+// CheckRows.
+func (r *BatchGetResult) Rows(tableName string) ([]Row, ValueDefiner) {
+	vd, ok := r.defs[tableName]
+	if !ok {
+		return nil, nil
+	}
+	rows := []Row{}
+	for _, k := range r.keys[tableName] {
+		if item, ok := r.items[tableName][keyString(k)]; ok {
+			rows = append(rows, Row{valueReader{item: item, def: vd}})
+		}
+	}
+	return rows, vd
+}
+
+// Get returns a reader of the item with the key written by the function. If
+// the item was not read, it returns an empty reader and ErrNotFound.
+func (r *BatchGetResult) Get(tableName string, key func(ValueWriter)) (ValueReader, error) {
+	k, err := buildKey(key)
+	if err != nil {
+		return NewValueReader(nil), err
+	}
+	item, ok := r.items[tableName][keyString(k)]
+	if !ok {
+		return NewValueReader(nil), ErrNotFound
+	}
+	return valueReader{item: item, def: r.defs[tableName]}, nil
+}
+
+// Missing returns the keys of the table that have no item, in the order they
+// were added. Keys that could not be read are not included; they are reported
+// by the *BatchGetError from Run.
+func (r *BatchGetResult) Missing(tableName string) []map[string]*dynamodb.AttributeValue {
+	failed := make(map[string]bool)
+	for _, k := range r.failed[tableName] {
+		failed[keyString(k)] = true
+	}
+	var missing []map[string]*dynamodb.AttributeValue
+	for _, k := range r.keys[tableName] {
+		s := keyString(k)
+		if _, ok := r.items[tableName][s]; !ok && !failed[s] {
+			missing = append(missing, k)
+		}
+	}
+	return missing
+}
+
+// BatchGetError reports the keys that could not be read, either because they
+// were still unprocessed when the retries ran out, or because DynamoDB
+// rejected the request that held them.
+type BatchGetError struct {
+	// Keys are the keys that could not be read, by table name.
+	Keys map[string][]map[string]*dynamodb.AttributeValue
+
+	// Err is the last error from DynamoDB, or nil if the keys failed only
+	// because they were unprocessed.
+	Err error
+}
+
+func newBatchGetError(group map[string]*dynamodb.KeysAndAttributes, err error) *BatchGetError {
+	keys := make(map[string][]map[string]*dynamodb.AttributeValue)
+	for table, ka := range group {
+		keys[table] = ka.Keys
+	}
+	return &BatchGetError{keys, err}
+}
+
+func (e *BatchGetError) Error() string {
+	n := 0
+	for _, keys := range e.Keys {
+		n += len(keys)
+	}
+	msg := fmt.Sprintf("dynamis: %d batch gets failed", n)
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the last error from DynamoDB.
+func (e *BatchGetError) Unwrap() error {
+	return e.Err
+}
+
+// keyString returns a string that identifies a key, for comparing keys. Key
+// attributes are strings, numbers or binary values. Numbers are compared by
+// value, so "1.0" and "1" are the same key, as they are to DynamoDB.
+func keyString(key map[string]*dynamodb.AttributeValue) string {
+	names := make([]string, 0, len(key))
+	for name := range key {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		val := key[name]
+		switch {
+		case val == nil:
+			parts[i] = name + "="
+		case val.S != nil:
+			parts[i] = name + "=S:" + *val.S
+		case val.N != nil:
+			n := *val.N
+			if r, ok := new(big.Rat).SetString(n); ok {
+				n = r.RatString()
+			}
+			parts[i] = name + "=N:" + n
+		case val.B != nil:
+			parts[i] = name + "=B:" + base64.StdEncoding.EncodeToString(val.B)
+		default:
+			parts[i] = name + "=" + val.String()
+		}
+	}
+	return strings.Join(parts, "\x00")
+}
@@ -0,0 +1,294 @@
+package dynamis
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestKeyString(t *testing.T) {
+	a := map[string]*dynamodb.AttributeValue{
+		"id": {S: aws.String("1")},
+		"n":  {N: aws.String("1")},
+	}
+	b := map[string]*dynamodb.AttributeValue{
+		"n":  {N: aws.String("1")},
+		"id": {S: aws.String("1")},
+	}
+	c := map[string]*dynamodb.AttributeValue{
+		"id": {N: aws.String("1")},
+		"n":  {N: aws.String("1")},
+	}
+	d := map[string]*dynamodb.AttributeValue{
+		"id": {B: []byte("1")},
+	}
+	if keyString(a) != keyString(b) {
+		t.Errorf("keyString() differs by order: %q, %q", keyString(a), keyString(b))
+	}
+	if keyString(a) == keyString(c) || keyString(c) == keyString(d) {
+		t.Errorf("keyString() ignores types: %q, %q, %q", keyString(a), keyString(c), keyString(d))
+	}
+
+	// Numbers are compared by value.
+	num := func(n string) map[string]*dynamodb.AttributeValue {
+		return map[string]*dynamodb.AttributeValue{"n": {N: aws.String(n)}}
+	}
+	for _, test := range [][2]string{{"1", "1.0"}, {"100", "1e2"}, {"0", "-0"}, {"1.5", "01.50"}} {
+		if keyString(num(test[0])) != keyString(num(test[1])) {
+			t.Errorf("keyString() differs for %s and %s", test[0], test[1])
+		}
+	}
+	if keyString(num("1")) == keyString(num("1.01")) {
+		t.Errorf("keyString() same for 1 and 1.01")
+	}
+}
+
+// fakeBatchGet serves BatchGetItem calls from fixed tables, and leaves the
+// last unprocessed keys of each table unprocessed.
+type fakeBatchGet struct {
+	tables      map[string]map[string]map[string]*dynamodb.AttributeValue
+	calls       []map[string]*dynamodb.KeysAndAttributes
+	unprocessed int
+	err         error
+}
+
+func (f *fakeBatchGet) get(input *dynamodb.BatchGetItemInput) (*dynamodb.BatchGetItemOutput, error) {
+	f.calls = append(f.calls, input.RequestItems)
+	if f.err != nil {
+		return nil, f.err
+	}
+	out := &dynamodb.BatchGetItemOutput{
+		Responses:       make(map[string][]map[string]*dynamodb.AttributeValue),
+		UnprocessedKeys: make(map[string]*dynamodb.KeysAndAttributes),
+	}
+	for table, ka := range input.RequestItems {
+		keys := ka.Keys
+		if n := f.unprocessed; n > 0 {
+			if n > len(keys) {
+				n = len(keys)
+			}
+			out.UnprocessedKeys[table] = &dynamodb.KeysAndAttributes{Keys: keys[len(keys)-n:]}
+			keys = keys[:len(keys)-n]
+		}
+		for _, k := range keys {
+			if item, ok := f.tables[table][keyString(k)]; ok {
+				out.Responses[table] = append(out.Responses[table], item)
+			}
+		}
+	}
+	return out, nil
+}
+
+func newFakeBatchGetter(f *fakeBatchGet) (*BatchGetter, *[]time.Duration) {
+	var sleeps []time.Duration
+	g := NewBatchGetter(nil)
+	g.get = f.get
+	g.sleep = func(d time.Duration) { sleeps = append(sleeps, d) }
+	return g, &sleeps
+}
+
+func idKey(id int) func(ValueWriter) {
+	return func(w ValueWriter) {
+		w.Int("id", id)
+	}
+}
+
+func numKey(n Decimal) func(ValueWriter) {
+	return func(w ValueWriter) {
+		w.Num("id", n)
+	}
+}
+
+func newFakeBatchTables(n int) map[string]map[string]map[string]*dynamodb.AttributeValue {
+	tables := map[string]map[string]map[string]*dynamodb.AttributeValue{
+		"users":  {},
+		"groups": {},
+	}
+	for i := 0; i < n; i++ {
+		for table := range tables {
+			item := make(map[string]*dynamodb.AttributeValue)
+			SetInt(item, "id", i)
+			SetStr(item, "name", table+strconv.Itoa(i))
+			tables[table][keyString(map[string]*dynamodb.AttributeValue{"id": item["id"]})] = item
+		}
+	}
+	return tables
+}
+
+func TestBatchGetter(t *testing.T) {
+	f := &fakeBatchGet{tables: newFakeBatchTables(150)}
+	g, sleeps := newFakeBatchGetter(f)
+	for i := 160; i >= 0; i-- {
+		if err := g.Get("users", idKey(i)); err != nil {
+			t.Fatalf("Get() error %s", err)
+		}
+	}
+	// Duplicates are read once.
+	g.Get("users", idKey(3))
+	g.Get("groups", idKey(3))
+	g.Get("groups", idKey(3))
+	g.Get("groups", numKey("3.0"))
+	g.Get("groups", idKey(200))
+	// Keys with non-canonical numbers match the items DynamoDB returns.
+	g.Get("groups", numKey("5.00"))
+
+	r, err := g.Run()
+	if err != nil {
+		t.Fatalf("Run() error %s", err)
+	}
+	// 164 unique keys in groups of 100.
+	if got, want := len(f.calls), 2; got != want {
+		t.Errorf("calls got %d, want %d", got, want)
+	}
+	n := 0
+	for _, call := range f.calls {
+		for _, ka := range call {
+			n += len(ka.Keys)
+		}
+	}
+	if n != 164 {
+		t.Errorf("keys sent got %d, want 164", n)
+	}
+	if len(*sleeps) != 0 {
+		t.Errorf("sleeps got %v, want none", *sleeps)
+	}
+
+	// Rows are in the order of their keys, and share a definer per table.
+	rows, vd := r.Rows("users")
+	if got, want := len(rows), 150; got != want {
+		t.Fatalf("Rows(users) len got %d, want %d", got, want)
+	}
+	if got, want := rows[0].Int("id"), 149; got != want {
+		t.Errorf("Rows(users) first got %d, want %d", got, want)
+	}
+	vd.Def("upper", func(r ValueReader) interface{} { return "U" + r.Str("name") })
+	if got, want := rows[1].Get("upper"), "Uusers148"; got != want {
+		t.Errorf("Rows(users) def got %#v, want %#v", got, want)
+	}
+	if rows, vd := r.Rows("missing"); rows != nil || vd != nil {
+		t.Errorf("Rows(missing) got (%#v, %#v)", rows, vd)
+	}
+
+	item, err := r.Get("groups", idKey(3))
+	if err != nil || item.Str("name") != "groups3" {
+		t.Errorf("Get(groups, 3) got (%#v, %v)", item, err)
+	}
+	if item, err := r.Get("groups", idKey(5)); err != nil || item.Str("name") != "groups5" {
+		t.Errorf("Get(groups, 5) got (%#v, %v)", item, err)
+	}
+	if _, err := r.Get("groups", idKey(4)); err != ErrNotFound {
+		t.Errorf("Get(groups, 4) got %v, want %v", err, ErrNotFound)
+	}
+
+	var missing []int
+	for _, k := range r.Missing("users") {
+		missing = append(missing, Int(k, "id"))
+	}
+	if want := []int{160, 159, 158, 157, 156, 155, 154, 153, 152, 151, 150}; !reflect.DeepEqual(missing, want) {
+		t.Errorf("Missing(users) got %v, want %v", missing, want)
+	}
+	if got := r.Missing("groups"); len(got) != 1 || Int(got[0], "id") != 200 {
+		t.Errorf("Missing(groups) got %#v", got)
+	}
+
+	// Keys added after Run don't change the result.
+	g.Get("users", idKey(500))
+	g.Get("extra", idKey(1))
+	if got := len(r.Missing("users")); got != len(missing) {
+		t.Errorf("Missing(users) after Get() got %d keys, want %d", got, len(missing))
+	}
+	if got := r.Missing("extra"); got != nil {
+		t.Errorf("Missing(extra) after Get() got %#v, want nil", got)
+	}
+}
+
+func TestBatchGetterRetry(t *testing.T) {
+	f := &fakeBatchGet{tables: newFakeBatchTables(10), unprocessed: 1}
+	g, sleeps := newFakeBatchGetter(f)
+	g.MaxRetries = 2
+	for i := 0; i < 5; i++ {
+		g.Get("users", idKey(i))
+	}
+	r, err := g.Run()
+	berr, ok := err.(*BatchGetError)
+	if !ok {
+		t.Fatalf("Run() got %#v, want *BatchGetError", err)
+	}
+	// Each call leaves the last key unprocessed, so it is never read.
+	if got, want := len(f.calls), 3; got != want {
+		t.Errorf("calls got %d, want %d", got, want)
+	}
+	if got, want := len(*sleeps), 2; got != want {
+		t.Errorf("sleeps got %d, want %d", got, want)
+	}
+	if keys := berr.Keys["users"]; len(keys) != 1 || Int(keys[0], "id") != 4 || berr.Err != nil {
+		t.Errorf("BatchGetError got %#v", berr)
+	}
+	if got, want := berr.Error(), "dynamis: 1 batch gets failed"; got != want {
+		t.Errorf("Error() got %q, want %q", got, want)
+	}
+	rows, _ := r.Rows("users")
+	if got, want := len(rows), 4; got != want {
+		t.Errorf("Rows() len got %d, want %d", got, want)
+	}
+	// Keys that failed are not missing.
+	if got := r.Missing("users"); len(got) != 0 {
+		t.Errorf("Missing() got %#v", got)
+	}
+}
+
+func TestBatchGetterError(t *testing.T) {
+	failure := errors.New("boom")
+	f := &fakeBatchGet{err: failure}
+	g, _ := newFakeBatchGetter(f)
+	g.Get("users", idKey(1))
+	if err := g.Get("users", func(w ValueWriter) { w.Str("id", "") }); err != ErrEmptyKey {
+		t.Errorf("Get(empty) got %v, want %v", err, ErrEmptyKey)
+	}
+	r, err := g.Run()
+	berr, ok := err.(*BatchGetError)
+	if !ok || berr.Unwrap() != failure || len(berr.Keys["users"]) != 1 {
+		t.Errorf("Run() got %#v", err)
+	}
+	if rows, _ := r.Rows("users"); len(rows) != 0 {
+		t.Errorf("Rows() got %#v", rows)
+	}
+}
+
+func TestTableBatchGetter(t *testing.T) {
+	a, b := newTable(), newTable()
+	if err := createStrTable(a, "a1", "a2"); err != nil {
+		t.Fatalf("Failed initializing: %s", err)
+	}
+	if err := createStrTable(b, "b1"); err != nil {
+		t.Fatalf("Failed initializing: %s", err)
+	}
+	key := func(s string) func(ValueWriter) {
+		return func(w ValueWriter) {
+			w.Str("str", s)
+		}
+	}
+	g := NewBatchGetter(a.db)
+	g.Get(a.name, key("a1"))
+	g.Get(a.name, key("a2"))
+	g.Get(a.name, key("a3"))
+	g.Get(b.name, key("b1"))
+	r, err := g.Run()
+	if err != nil {
+		t.Fatalf("Run() error %s", err)
+	}
+	if rows, _ := r.Rows(a.name); len(rows) != 2 || rows[0].Str("str") != "a1" {
+		t.Errorf("Rows(a) got %#v", rows)
+	}
+	if rows, _ := r.Rows(b.name); len(rows) != 1 {
+		t.Errorf("Rows(b) got %#v", rows)
+	}
+	if missing := r.Missing(a.name); len(missing) != 1 || Str(missing[0], "str") != "a3" {
+		t.Errorf("Missing(a) got %#v", missing)
+	}
+}
@@ -1,20 +1,76 @@
 package dynamis
 
 import (
+	"sync"
+
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 )
 
-// CheckRowCount returns the number of records in a table.
+// CheckRowCount returns the number of records in a table. If an error occurs,
+// it returns -1.
 func CheckRowCount(db *dynamodb.DynamoDB, tableName string) int {
-	resp, err := db.Scan(&dynamodb.ScanInput{
+	n, err := CheckRowCountE(db, tableName)
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// CheckRowCountE returns the number of records in a table, or the error from
+// DynamoDB.
+func CheckRowCountE(db *dynamodb.DynamoDB, tableName string) (int, error) {
+	return countRows(db, &dynamodb.ScanInput{
 		TableName: aws.String(tableName),
-		Select:    aws.String(dynamodb.SelectCount),
+	})
+}
+
+// countRows counts the rows matched by the scan, following LastEvaluatedKey
+// and summing the count of each page.
+func countRows(db *dynamodb.DynamoDB, input *dynamodb.ScanInput) (int, error) {
+	input.Select = aws.String(dynamodb.SelectCount)
+	n := 0
+	err := db.ScanPages(input, func(page *dynamodb.ScanOutput, last bool) bool {
+		n += int(aws.Int64Value(page.Count))
+		return true
 	})
 	if err != nil {
-		return -1
+		return 0, err
 	}
-	return int(*resp.Count)
+	return n, nil
+}
+
+// countRowsParallel counts the rows matched by the scan by splitting it into
+// segments that are scanned concurrently. It returns the first error from any
+// segment.
+func countRowsParallel(db *dynamodb.DynamoDB, input dynamodb.ScanInput, segments int) (int, error) {
+	if segments < 2 {
+		return countRows(db, &input)
+	}
+	var (
+		wg     sync.WaitGroup
+		counts = make([]int, segments)
+		errs   = make([]error, segments)
+	)
+	for i := 0; i < segments; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			in := input
+			in.Segment = aws.Int64(int64(i))
+			in.TotalSegments = aws.Int64(int64(segments))
+			counts[i], errs[i] = countRows(db, &in)
+		}(i)
+	}
+	wg.Wait()
+	n := 0
+	for i, err := range errs {
+		if err != nil {
+			return 0, err
+		}
+		n += counts[i]
+	}
+	return n, nil
 }
 
 // Row is a generic accessor for any dynamodb row. It implements ValueReader,
@@ -27,40 +83,173 @@ type Row struct {
 // is unspecified. If an error occurs, the rows will be nil. The returned
 // ValueDefiner can be used to define access to custom types across all rows.
 func CheckRows(db *dynamodb.DynamoDB, tableName string) ([]Row, ValueDefiner) {
-	resp, err := db.Scan(&dynamodb.ScanInput{
-		TableName: aws.String(tableName),
-	})
+	rows, vd, err := CheckRowsE(db, tableName)
 	if err != nil {
 		return nil, nil
 	}
+	return rows, vd
+}
+
+// CheckRowsE is like CheckRows, but returns the error from DynamoDB.
+func CheckRowsE(db *dynamodb.DynamoDB, tableName string) ([]Row, ValueDefiner, error) {
+	return scanAll(db, &dynamodb.ScanInput{
+		TableName: aws.String(tableName),
+	})
+}
+
+// scanAll returns every row returned by the scan, sharing one definer.
+func scanAll(db *dynamodb.DynamoDB, input *dynamodb.ScanInput) ([]Row, ValueDefiner, error) {
 	vd := newValueDefiner()
-	rows := make([]Row, len(resp.Items))
-	for i, item := range resp.Items {
-		reader := valueReader{item, vd}
-		rows[i] = Row{reader}
+	rows := []Row{}
+	err := scanRows(db, input, vd, func(row Row) error {
+		rows = append(rows, row)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
 	}
-	return rows, vd
+	return rows, vd, nil
+}
+
+// scanRows calls f with each row returned by the scan, following
+// LastEvaluatedKey until the scan is complete. Every row shares the definer.
+// If f returns an error, the scan stops and returns it.
+func scanRows(db *dynamodb.DynamoDB, input *dynamodb.ScanInput, vd valueDefiner, f func(Row) error) error {
+	return pageRows(func(page func([]map[string]*dynamodb.AttributeValue) bool) error {
+		return db.ScanPages(input, func(out *dynamodb.ScanOutput, last bool) bool {
+			return page(out.Items)
+		})
+	}, vd, f)
+}
+
+// pageRows calls f with each row of the pages read by pages, which calls page
+// with the items of each page until it returns false. Every row shares the
+// definer. If f returns an error, reading stops and returns it.
+func pageRows(pages func(page func([]map[string]*dynamodb.AttributeValue) bool) error, vd valueDefiner, f func(Row) error) error {
+	var ferr error
+	err := pages(func(items []map[string]*dynamodb.AttributeValue) bool {
+		for _, item := range items {
+			if ferr = f(Row{valueReader{item: item, def: vd}}); ferr != nil {
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	return ferr
 }
 
 // Table is a convient wrapper over any DynamoDB table.
 type Table struct {
-	db        *dynamodb.DynamoDB
-	tableName string
+	db         *dynamodb.DynamoDB
+	tableName  string
+	projection *Projection
+	filter     *Condition
 }
 
 // CheckTable initializes a new wrapper over the table.
 func CheckTable(db *dynamodb.DynamoDB, tableName string) Table {
-	return Table{db, tableName}
+	return Table{db: db, tableName: tableName}
+}
+
+// scanInput returns the input to scan the whole table.
+func (t Table) scanInput() (*dynamodb.ScanInput, error) {
+	b := newExprBuilder(DefaultCodecs)
+	projection, err := t.projection.apply(b)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := t.filterExpr(b)
+	if err != nil {
+		return nil, err
+	}
+	return &dynamodb.ScanInput{
+		TableName:                 aws.String(t.tableName),
+		ProjectionExpression:      projection,
+		FilterExpression:          filter,
+		ExpressionAttributeNames:  b.attrNames(),
+		ExpressionAttributeValues: b.attrValues(),
+	}, nil
 }
 
-// RowCount returns the number of rows in the table.
+// countInput returns the input to count the rows in the table. Counts don't
+// read attributes, so there is no projection, but the filter applies.
+func (t Table) countInput() (*dynamodb.ScanInput, error) {
+	t.projection = nil
+	return t.scanInput()
+}
+
+// RowCount returns the number of rows in the table. It returns -1 if an error
+// occurs.
 func (t Table) RowCount() int {
-	return CheckRowCount(t.db, t.tableName)
+	n, err := t.RowCountE()
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// RowCountE returns the number of rows in the table, or the error from
+// DynamoDB.
+func (t Table) RowCountE() (int, error) {
+	input, err := t.countInput()
+	if err != nil {
+		return 0, err
+	}
+	return countRows(t.db, input)
+}
+
+// ParallelRowCount returns the number of rows in the table, scanning the table
+// in segments concurrently. It returns -1 if an error occurs.
+func (t Table) ParallelRowCount(segments int) int {
+	n, err := t.ParallelRowCountE(segments)
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// ParallelRowCountE is like ParallelRowCount, but returns the error from
+// DynamoDB.
+func (t Table) ParallelRowCountE(segments int) (int, error) {
+	input, err := t.countInput()
+	if err != nil {
+		return 0, err
+	}
+	return countRowsParallel(t.db, *input, segments)
 }
 
 // Rows returns a simple accessor for each row in the table. The rows are
 // returned in no particular order. The returned ValueDefiner can be used to
 // initialize access to complex values.
 func (t Table) Rows() ([]Row, ValueDefiner) {
-	return CheckRows(t.db, t.tableName)
+	rows, vd, err := t.RowsE()
+	if err != nil {
+		return nil, nil
+	}
+	return rows, vd
+}
+
+// RowsE is like Rows, but returns the error from DynamoDB.
+func (t Table) RowsE() ([]Row, ValueDefiner, error) {
+	input, err := t.scanInput()
+	if err != nil {
+		return nil, nil, err
+	}
+	return scanAll(t.db, input)
+}
+
+// EachRow calls f with each row in the table, one page of the scan at a time,
+// so large tables don't need to fit in memory. The rows share a ValueDefiner,
+// so a definition made on any row applies to every row after it. If f returns
+// an error, EachRow stops and returns it. Otherwise it returns any error from
+// the scan.
+func (t Table) EachRow(f func(Row) error) error {
+	input, err := t.scanInput()
+	if err != nil {
+		return err
+	}
+	return scanRows(t.db, input, newValueDefiner(), f)
 }
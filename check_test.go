@@ -1,11 +1,15 @@
 package dynamis
 
 import (
+	"errors"
 	"reflect"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 )
 
@@ -315,3 +319,220 @@ func TestCheckTable(t *testing.T) {
 		t.Errorf("ValueDefiner is nil")
 	}
 }
+
+// createStrTable creates a table keyed by "str" and puts a row for each key.
+func createStrTable(t table, keys ...string) error {
+	_, err := t.db.CreateTable(&dynamodb.CreateTableInput{
+		TableName: aws.String(t.name),
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{
+				AttributeName: aws.String("str"),
+				AttributeType: aws.String("S"),
+			},
+		},
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{
+				AttributeName: aws.String("str"),
+				KeyType:       aws.String("HASH"),
+			},
+		},
+		ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+			ReadCapacityUnits:  aws.Int64(1),
+			WriteCapacityUnits: aws.Int64(1),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		_, err = t.db.PutItem(&dynamodb.PutItemInput{
+			TableName: aws.String(t.name),
+			Item: map[string]*dynamodb.AttributeValue{
+				"str": {
+					S: aws.String(key),
+				},
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestCheckRowsPaginated(t *testing.T) {
+	tbl := newTable()
+	if err := createStrTable(tbl, "a", "b", "c"); err != nil {
+		t.Fatalf("Failed initializing: %s", err)
+	}
+	// A limit of one row forces a page per row.
+	var got []string
+	err := scanRows(tbl.db, &dynamodb.ScanInput{
+		TableName: aws.String(tbl.name),
+		Limit:     aws.Int64(1),
+	}, newValueDefiner(), func(row Row) error {
+		got = append(got, row.Str("str"))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("scanRows() error %s", err)
+	}
+	sort.Strings(got)
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("scanRows() got %#v, want %#v", got, want)
+	}
+}
+
+func TestPageRows(t *testing.T) {
+	item := func(s string) map[string]*dynamodb.AttributeValue {
+		return map[string]*dynamodb.AttributeValue{"str": {S: aws.String(s)}}
+	}
+	pages := [][]map[string]*dynamodb.AttributeValue{
+		{item("a"), item("b")},
+		{item("c")},
+	}
+	pagerErr := errors.New("pager failed")
+	stop := errors.New("stop")
+	tests := []struct {
+		err   error
+		until string
+		want  []string
+		reads int
+	}{
+		{want: []string{"a", "b", "c"}, reads: 2},
+		{until: "a", want: []string{"a"}, reads: 1},
+		{until: "c", want: []string{"a", "b", "c"}, reads: 2},
+		{err: pagerErr, want: []string{"a", "b", "c"}, reads: 2},
+	}
+	for i, test := range tests {
+		var got []string
+		reads := 0
+		err := pageRows(func(page func([]map[string]*dynamodb.AttributeValue) bool) error {
+			for _, items := range pages {
+				reads++
+				if !page(items) {
+					break
+				}
+			}
+			return test.err
+		}, newValueDefiner(), func(row Row) error {
+			got = append(got, row.Str("str"))
+			if row.Str("str") == test.until {
+				return stop
+			}
+			return nil
+		})
+		wantErr := test.err
+		if wantErr == nil && test.until != "" {
+			wantErr = stop
+		}
+		if err != wantErr {
+			t.Errorf("%d pageRows() got error %v, want %v", i, err, wantErr)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%d pageRows() got %#v, want %#v", i, got, test.want)
+		}
+		if reads != test.reads {
+			t.Errorf("%d pageRows() read %d pages, want %d", i, reads, test.reads)
+		}
+	}
+}
+
+func TestTableEachRow(t *testing.T) {
+	tbl := newTable()
+	if err := createStrTable(tbl, "a", "b", "c"); err != nil {
+		t.Fatalf("Failed initializing: %s", err)
+	}
+	table := CheckTable(tbl.db, tbl.name)
+
+	// Definitions made on one row apply to the rows after it.
+	var got []string
+	err := table.EachRow(func(row Row) error {
+		if len(got) == 0 {
+			row.Def("upper", func(r ValueReader) interface{} {
+				return strings.ToUpper(r.Str("str"))
+			})
+		}
+		got = append(got, row.Get("upper").(string))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("EachRow() error %s", err)
+	}
+	sort.Strings(got)
+	if want := []string{"A", "B", "C"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("EachRow() got %#v, want %#v", got, want)
+	}
+
+	// Errors from the function stop the scan.
+	stop := errors.New("stop")
+	n := 0
+	err = table.EachRow(func(Row) error {
+		n++
+		return stop
+	})
+	if err != stop || n != 1 {
+		t.Errorf("EachRow() got (%d, %v), want (1, %v)", n, err, stop)
+	}
+
+	// Scan errors are returned.
+	if err := CheckTable(tbl.db, "missing").EachRow(func(Row) error { return nil }); err == nil {
+		t.Errorf("EachRow(missing) want error")
+	}
+}
+
+func TestCheckErrors(t *testing.T) {
+	tbl := newTable()
+	notFound := func(err error) bool {
+		aerr, ok := err.(awserr.Error)
+		return ok && aerr.Code() == dynamodb.ErrCodeResourceNotFoundException
+	}
+	if n, err := CheckRowCountE(tbl.db, tbl.name); n != 0 || !notFound(err) {
+		t.Errorf("CheckRowCountE() got (%d, %v)", n, err)
+	}
+	if rows, vd, err := CheckRowsE(tbl.db, tbl.name); rows != nil || vd != nil || !notFound(err) {
+		t.Errorf("CheckRowsE() got (%#v, %#v, %v)", rows, vd, err)
+	}
+	table := CheckTable(tbl.db, tbl.name)
+	if _, err := table.RowCountE(); !notFound(err) {
+		t.Errorf("RowCountE() got %v", err)
+	}
+	if _, _, err := table.RowsE(); !notFound(err) {
+		t.Errorf("RowsE() got %v", err)
+	}
+
+	if err := createStrTable(tbl, "a"); err != nil {
+		t.Fatalf("Failed initializing: %s", err)
+	}
+	if n, err := table.RowCountE(); n != 1 || err != nil {
+		t.Errorf("RowCountE() got (%d, %v)", n, err)
+	}
+	if rows, vd, err := table.RowsE(); len(rows) != 1 || vd == nil || err != nil {
+		t.Errorf("RowsE() got (%#v, %#v, %v)", rows, vd, err)
+	}
+}
+
+func TestCheckRowCountPaginated(t *testing.T) {
+	tbl := newTable()
+	if err := createStrTable(tbl, "a", "b", "c", "d", "e"); err != nil {
+		t.Fatalf("Failed initializing: %s", err)
+	}
+	// A limit of one row forces a page per row.
+	n, err := countRows(tbl.db, &dynamodb.ScanInput{
+		TableName: aws.String(tbl.name),
+		Limit:     aws.Int64(1),
+	})
+	if n != 5 || err != nil {
+		t.Errorf("countRows() got (%d, %v), want 5", n, err)
+	}
+
+	table := CheckTable(tbl.db, tbl.name)
+	for _, segments := range []int{0, 1, 2, 4, 8} {
+		if got, want := table.ParallelRowCount(segments), 5; got != want {
+			t.Errorf("ParallelRowCount(%d) got %d, want %d", segments, got, want)
+		}
+	}
+	if got, want := CheckTable(tbl.db, "missing").ParallelRowCount(4), -1; got != want {
+		t.Errorf("ParallelRowCount(missing) got %d, want %d", got, want)
+	}
+}
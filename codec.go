@@ -0,0 +1,244 @@
+package dynamis
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/url"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// Codec converts values of one Go type to and from DynamoDB attribute values.
+type Codec struct {
+	// Encode converts a value into an attribute value. If it returns nil,
+	// nothing is stored, just as SetStr skips empty strings.
+	Encode func(v interface{}) (*dynamodb.AttributeValue, error)
+
+	// Decode converts an attribute value back into the Go type.
+	Decode func(val *dynamodb.AttributeValue) (interface{}, error)
+}
+
+// Codecs is a registry of Codecs keyed by Go type. Marshal, Unmarshal and the
+// Encode and Decode methods of ValueWriter and ValueReader consult it before
+// falling back to the default conversion for a type.
+type Codecs struct {
+	mu     sync.RWMutex
+	codecs map[reflect.Type]Codec
+}
+
+// DefaultCodecs is the registry used by the package functions Marshal and
+// Unmarshal, and by readers and writers from NewValueReader and
+// NewValueWriter.
+var DefaultCodecs = NewCodecs()
+
+// NewCodecs initializes a registry holding the built-in codecs for time.Time,
+// time.Duration, net.IP, url.URL, big.Int, big.Float and Decimal.
+func NewCodecs() *Codecs {
+	c := &Codecs{codecs: make(map[reflect.Type]Codec)}
+	c.Register(time.Time{}, timeCodec)
+	c.Register(time.Duration(0), durationCodec)
+	c.Register(net.IP{}, ipCodec)
+	c.Register(url.URL{}, urlCodec)
+	c.Register(big.Int{}, bigIntCodec)
+	c.Register(big.Float{}, bigFloatCodec)
+	c.Register(Decimal(""), decimalCodec)
+	return c
+}
+
+// Register adds a codec to the DefaultCodecs registry for the type of
+// example.
+func Register(example interface{}, codec Codec) {
+	DefaultCodecs.Register(example, codec)
+}
+
+// Register adds a codec for the type of example, replacing any codec already
+// registered for that type.
+func (c *Codecs) Register(example interface{}, codec Codec) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.codecs[reflect.TypeOf(example)] = codec
+}
+
+func (c *Codecs) lookup(t reflect.Type) (Codec, bool) {
+	if c == nil {
+		return Codec{}, false
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	codec, ok := c.codecs[t]
+	return codec, ok
+}
+
+// NewValueReader initializes a ValueReader over an item that decodes values
+// with this registry.
+func (c *Codecs) NewValueReader(item map[string]*dynamodb.AttributeValue) ValueReader {
+	def := newValueDefiner()
+	def.codecs = c
+	return valueReader{item: item, def: def}
+}
+
+// NewValueWriter initializes a ValueWriter over an item that encodes values
+// with this registry.
+func (c *Codecs) NewValueWriter(item map[string]*dynamodb.AttributeValue) ValueWriter {
+	return ValueWriter{item: item, codecs: c}
+}
+
+// encode stores v in the item at key.
+func (c *Codecs) encode(item map[string]*dynamodb.AttributeValue, key string, v interface{}, path string) error {
+	if v == nil {
+		return nil
+	}
+	return c.marshalValue(item, key, reflect.ValueOf(v), false, path)
+}
+
+// decode reads the attribute at key into the value pointed to by v. A NULL
+// value sets v to its zero value.
+func (c *Codecs) decode(item map[string]*dynamodb.AttributeValue, key string, v interface{}, path string) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return &ValueError{path, fmt.Errorf("cannot decode into %T, want non-nil pointer", v)}
+	}
+	if !Has(item, key) {
+		return &ValueError{path, ErrMissing}
+	}
+	return c.unmarshalValue(item[key], rv.Elem(), path)
+}
+
+// zeroValue sets the value pointed to by v to its zero value.
+func zeroValue(v interface{}) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv.Elem().Set(reflect.Zero(rv.Elem().Type()))
+	}
+}
+
+// timeCodec stores times as RFC3339 strings with nanoseconds, and reads them
+// like Time. The zero time is not stored.
+var timeCodec = Codec{
+	Encode: func(v interface{}) (*dynamodb.AttributeValue, error) {
+		t := v.(time.Time)
+		if t.IsZero() {
+			return nil, nil
+		}
+		return formatTime(t, time.RFC3339Nano), nil
+	},
+	Decode: func(val *dynamodb.AttributeValue) (interface{}, error) {
+		return parseTime(val, "")
+	},
+}
+
+// durationCodec stores durations as a number of nanoseconds.
+var durationCodec = Codec{
+	Encode: func(v interface{}) (*dynamodb.AttributeValue, error) {
+		d := v.(time.Duration)
+		return &dynamodb.AttributeValue{N: aws.String(strconv.FormatInt(int64(d), 10))}, nil
+	},
+	Decode: func(val *dynamodb.AttributeValue) (interface{}, error) {
+		if val.N == nil {
+			return nil, ErrType
+		}
+		n, err := strconv.ParseInt(*val.N, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return time.Duration(n), nil
+	},
+}
+
+// ipCodec stores IP addresses in their string form. An empty IP is not
+// stored.
+var ipCodec = Codec{
+	Encode: func(v interface{}) (*dynamodb.AttributeValue, error) {
+		ip := v.(net.IP)
+		if len(ip) == 0 {
+			return nil, nil
+		}
+		return &dynamodb.AttributeValue{S: aws.String(ip.String())}, nil
+	},
+	Decode: func(val *dynamodb.AttributeValue) (interface{}, error) {
+		if val.S == nil {
+			return nil, ErrType
+		}
+		ip := net.ParseIP(*val.S)
+		if ip == nil {
+			return nil, errors.New("invalid IP address " + strconv.Quote(*val.S))
+		}
+		return ip, nil
+	},
+}
+
+// urlCodec stores URLs in their string form. An empty URL is not stored.
+var urlCodec = Codec{
+	Encode: func(v interface{}) (*dynamodb.AttributeValue, error) {
+		u := v.(url.URL)
+		s := u.String()
+		if s == "" {
+			return nil, nil
+		}
+		return &dynamodb.AttributeValue{S: aws.String(s)}, nil
+	},
+	Decode: func(val *dynamodb.AttributeValue) (interface{}, error) {
+		if val.S == nil {
+			return nil, ErrType
+		}
+		u, err := url.Parse(*val.S)
+		if err != nil {
+			return nil, err
+		}
+		return *u, nil
+	},
+}
+
+// bigIntCodec stores big.Int values as numbers, like SetBigInt.
+var bigIntCodec = Codec{
+	Encode: func(v interface{}) (*dynamodb.AttributeValue, error) {
+		i := v.(big.Int)
+		item := make(map[string]*dynamodb.AttributeValue)
+		err := SetBigInt(item, listKey, &i)
+		return item[listKey], err
+	},
+	Decode: func(val *dynamodb.AttributeValue) (interface{}, error) {
+		i, err := bigIntE(map[string]*dynamodb.AttributeValue{listKey: val}, listKey)
+		if err != nil {
+			return nil, err
+		}
+		return *i, nil
+	},
+}
+
+// bigFloatCodec stores big.Float values as numbers, like SetBigFloat.
+var bigFloatCodec = Codec{
+	Encode: func(v interface{}) (*dynamodb.AttributeValue, error) {
+		f := v.(big.Float)
+		item := make(map[string]*dynamodb.AttributeValue)
+		err := SetBigFloat(item, listKey, &f)
+		return item[listKey], err
+	},
+	Decode: func(val *dynamodb.AttributeValue) (interface{}, error) {
+		f, err := bigFloatE(map[string]*dynamodb.AttributeValue{listKey: val}, listKey)
+		if err != nil {
+			return nil, err
+		}
+		return *f, nil
+	},
+}
+
+// decimalCodec stores Decimal values as numbers, like SetNum.
+var decimalCodec = Codec{
+	Encode: func(v interface{}) (*dynamodb.AttributeValue, error) {
+		item := make(map[string]*dynamodb.AttributeValue)
+		err := SetNum(item, listKey, v.(Decimal))
+		return item[listKey], err
+	},
+	Decode: func(val *dynamodb.AttributeValue) (interface{}, error) {
+		n, err := numE(map[string]*dynamodb.AttributeValue{listKey: val}, listKey)
+		return Decimal(n), err
+	},
+}
@@ -0,0 +1,207 @@
+package dynamis
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+type codecRecord struct {
+	At      time.Time     `dynamis:"at"`
+	Created *time.Time    `dynamis:"created"`
+	TTL     time.Duration `dynamis:"ttl"`
+	IP      net.IP        `dynamis:"ip"`
+	Home    url.URL       `dynamis:"home"`
+	Link    *url.URL      `dynamis:"link"`
+}
+
+func TestCodecsBuiltin(t *testing.T) {
+	at := time.Date(2015, 9, 16, 1, 2, 3, 4, time.UTC)
+	link, _ := url.Parse("https://example.com/a?b=c")
+	in := codecRecord{
+		At:      at,
+		Created: &at,
+		TTL:     90 * time.Second,
+		IP:      net.ParseIP("10.0.0.1"),
+		Home:    url.URL{Scheme: "http", Host: "example.com"},
+		Link:    link,
+	}
+	item, err := Marshal(in)
+	if err != nil {
+		t.Fatalf("Marshal() error %s", err)
+	}
+	want := map[string]*dynamodb.AttributeValue{
+		"at":      {S: aws.String("2015-09-16T01:02:03.000000004Z")},
+		"created": {S: aws.String("2015-09-16T01:02:03.000000004Z")},
+		"ttl":     {N: aws.String("90000000000")},
+		"ip":      {S: aws.String("10.0.0.1")},
+		"home":    {S: aws.String("http://example.com")},
+		"link":    {S: aws.String("https://example.com/a?b=c")},
+	}
+	if !reflect.DeepEqual(item, want) {
+		t.Errorf("Marshal() got %#v, want %#v", item, want)
+	}
+	var out codecRecord
+	if err := Unmarshal(item, &out); err != nil {
+		t.Fatalf("Unmarshal() error %s", err)
+	}
+	if !reflect.DeepEqual(out, in) {
+		t.Errorf("Unmarshal() got %#v, want %#v", out, in)
+	}
+
+	// Empty values are not stored.
+	item, err = Marshal(codecRecord{})
+	if err != nil {
+		t.Fatalf("Marshal() error %s", err)
+	}
+	want = map[string]*dynamodb.AttributeValue{
+		"ttl": {N: aws.String("0")},
+	}
+	if !reflect.DeepEqual(item, want) {
+		t.Errorf("Marshal() got %#v, want %#v", item, want)
+	}
+
+	// Decode failures report the path.
+	err = Unmarshal(map[string]*dynamodb.AttributeValue{
+		"ip": {S: aws.String("nope")},
+	}, &out)
+	if verr, ok := err.(*ValueError); !ok || verr.Path != "ip" {
+		t.Errorf("Unmarshal() got %#v", err)
+	}
+}
+
+type shout string
+
+var shoutCodec = Codec{
+	Encode: func(v interface{}) (*dynamodb.AttributeValue, error) {
+		return &dynamodb.AttributeValue{S: aws.String(strings.ToUpper(string(v.(shout))))}, nil
+	},
+	Decode: func(val *dynamodb.AttributeValue) (interface{}, error) {
+		if val.S == nil {
+			return nil, errors.New("not a string")
+		}
+		return shout(strings.ToLower(*val.S)), nil
+	},
+}
+
+func TestCodecsScoped(t *testing.T) {
+	c := NewCodecs()
+	c.Register(shout(""), shoutCodec)
+
+	type record struct {
+		S shout `dynamis:"s"`
+	}
+	item, err := c.Marshal(record{"hi"})
+	if err != nil {
+		t.Fatalf("Marshal() error %s", err)
+	}
+	if got, want := Str(item, "s"), "HI"; got != want {
+		t.Errorf("Marshal() got %#v, want %#v", got, want)
+	}
+	var out record
+	if err := c.Unmarshal(item, &out); err != nil || out.S != "hi" {
+		t.Errorf("Unmarshal() got (%#v, %v)", out, err)
+	}
+
+	// The default registry does not see scoped registrations.
+	item, _ = Marshal(record{"hi"})
+	if got, want := Str(item, "s"), "hi"; got != want {
+		t.Errorf("Marshal() got %#v, want %#v", got, want)
+	}
+
+	// Readers and writers share the registry.
+	item = make(map[string]*dynamodb.AttributeValue)
+	w := c.NewValueWriter(item)
+	if err := w.Encode("s", shout("hey")); err != nil {
+		t.Fatalf("Encode() error %s", err)
+	}
+	w.Map("m", func(sub ValueWriter) {
+		sub.Encode("s", shout("nested"))
+	})
+	w.List("l", func(l ListWriter) {
+		l.Encode(shout("listed"))
+	})
+	if got, want := Str(item, "s"), "HEY"; got != want {
+		t.Errorf("Encode() got %#v, want %#v", got, want)
+	}
+	if got, want := Str(Map(item, "m"), "s"), "NESTED"; got != want {
+		t.Errorf("nested Encode() got %#v, want %#v", got, want)
+	}
+	if got, want := List(item, "l"), []*dynamodb.AttributeValue{{S: aws.String("LISTED")}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("list Encode() got %#v, want %#v", got, want)
+	}
+
+	var s shout
+	r := c.NewValueReader(item)
+	r.Decode("s", &s)
+	if s != "hey" {
+		t.Errorf("Decode() got %#v", s)
+	}
+	r.At("m").Decode("s", &s)
+	if s != "nested" {
+		t.Errorf("nested Decode() got %#v", s)
+	}
+	if err := r.Strict().DecodeE("s", &s); err != nil || s != "hey" {
+		t.Errorf("DecodeE() got (%#v, %v)", s, err)
+	}
+}
+
+func TestValueReaderDecode(t *testing.T) {
+	r := NewValueReader(map[string]*dynamodb.AttributeValue{
+		"at":  {S: aws.String("2015-09-16T00:00:00Z")},
+		"bad": {S: aws.String("yesterday")},
+	})
+	var at time.Time
+	r.Decode("at", &at)
+	if want := time.Date(2015, 9, 16, 0, 0, 0, 0, time.UTC); !at.Equal(want) {
+		t.Errorf("Decode() got %s, want %s", at, want)
+	}
+
+	// Failures set the zero value.
+	r.Decode("bad", &at)
+	if !at.IsZero() {
+		t.Errorf("Decode(bad) got %s, want zero", at)
+	}
+	at = time.Now()
+	r.Decode("missing", &at)
+	if !at.IsZero() {
+		t.Errorf("Decode(missing) got %s, want zero", at)
+	}
+
+	// Strict decoding reports the path.
+	err := r.Strict().DecodeE("missing", &at)
+	if verr, ok := err.(*ValueError); !ok || verr.Path != "missing" || verr.Err != ErrMissing {
+		t.Errorf("DecodeE(missing) got %#v", err)
+	}
+	err = r.Strict().DecodeE("at", at)
+	if err == nil {
+		t.Errorf("DecodeE(non-pointer) want error")
+	}
+}
+
+func TestValueWriterEncode(t *testing.T) {
+	item := make(map[string]*dynamodb.AttributeValue)
+	w := NewValueWriter(item)
+	if err := w.Encode("ttl", time.Minute); err != nil {
+		t.Fatalf("Encode() error %s", err)
+	}
+	if err := w.Encode("nil", nil); err != nil {
+		t.Fatalf("Encode(nil) error %s", err)
+	}
+	if err := w.Encode("c", make(chan int)); err == nil {
+		t.Errorf("Encode(chan) want error")
+	}
+	want := map[string]*dynamodb.AttributeValue{
+		"ttl": {N: aws.String("60000000000")},
+	}
+	if !reflect.DeepEqual(item, want) {
+		t.Errorf("Encode() got %#v, want %#v", item, want)
+	}
+}
@@ -0,0 +1,222 @@
+package dynamis
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// Condition is a condition expression, such as the ConditionExpression of a
+// PutItem or the FilterExpression of a Scan. Build conditions with Cond and
+// combine them with And, Or and Not.
+type Condition struct {
+	build func(b *exprBuilder) (string, error)
+}
+
+// CondBuilder builds Conditions. Use the Cond variable rather than making your
+// own.
+type CondBuilder struct{}
+
+// Cond builds Conditions. For example:
+//
+//	Cond.AttributeNotExists("user_id").Or(Cond.Eq("version", 3))
+//
+// Attribute names are document paths such as "address.city" or
+// "events[3].type", and every name and value is given a placeholder. Values
+// are encoded like ValueWriter.Encode, so they may be strings, numbers,
+// binary values, sets or any type with a registered Codec. Empty values such
+// as "" are not stored by the Set helpers, so they can't be compared either,
+// and Build reports them as errors.
+var Cond CondBuilder
+
+// compare builds a condition that compares the attribute at path to a value.
+func compare(path, op string, v interface{}) Condition {
+	return Condition{func(b *exprBuilder) (string, error) {
+		name, err := b.name(path)
+		if err != nil {
+			return "", err
+		}
+		val, err := b.value(path, v)
+		if err != nil {
+			return "", err
+		}
+		return name + " " + op + " " + val, nil
+	}}
+}
+
+// function builds a condition that calls a function with the attribute at
+// path and any number of values.
+func function(fn, path string, vs ...interface{}) Condition {
+	return Condition{func(b *exprBuilder) (string, error) {
+		name, err := b.name(path)
+		if err != nil {
+			return "", err
+		}
+		args := []string{name}
+		for _, v := range vs {
+			val, err := b.value(path, v)
+			if err != nil {
+				return "", err
+			}
+			args = append(args, val)
+		}
+		return fn + "(" + strings.Join(args, ", ") + ")", nil
+	}}
+}
+
+// Eq matches when the attribute at path equals v.
+func (CondBuilder) Eq(path string, v interface{}) Condition {
+	return compare(path, "=", v)
+}
+
+// Ne matches when the attribute at path does not equal v. It also matches
+// when the attribute does not exist.
+func (CondBuilder) Ne(path string, v interface{}) Condition {
+	return compare(path, "<>", v)
+}
+
+// Lt matches when the attribute at path is less than v.
+func (CondBuilder) Lt(path string, v interface{}) Condition {
+	return compare(path, "<", v)
+}
+
+// Le matches when the attribute at path is less than or equal to v.
+func (CondBuilder) Le(path string, v interface{}) Condition {
+	return compare(path, "<=", v)
+}
+
+// Gt matches when the attribute at path is greater than v.
+func (CondBuilder) Gt(path string, v interface{}) Condition {
+	return compare(path, ">", v)
+}
+
+// Ge matches when the attribute at path is greater than or equal to v.
+func (CondBuilder) Ge(path string, v interface{}) Condition {
+	return compare(path, ">=", v)
+}
+
+// Between matches when the attribute at path is from lo to hi, inclusive.
+func (CondBuilder) Between(path string, lo, hi interface{}) Condition {
+	return Condition{func(b *exprBuilder) (string, error) {
+		name, err := b.name(path)
+		if err != nil {
+			return "", err
+		}
+		l, err := b.value(path, lo)
+		if err != nil {
+			return "", err
+		}
+		h, err := b.value(path, hi)
+		if err != nil {
+			return "", err
+		}
+		return name + " BETWEEN " + l + " AND " + h, nil
+	}}
+}
+
+// In matches when the attribute at path equals any of the values.
+func (CondBuilder) In(path string, vs ...interface{}) Condition {
+	return Condition{func(b *exprBuilder) (string, error) {
+		if len(vs) == 0 {
+			return "", errors.New("dynamis: In needs at least one value")
+		}
+		name, err := b.name(path)
+		if err != nil {
+			return "", err
+		}
+		vals := make([]string, len(vs))
+		for i, v := range vs {
+			if vals[i], err = b.value(path, v); err != nil {
+				return "", err
+			}
+		}
+		return name + " IN (" + strings.Join(vals, ", ") + ")", nil
+	}}
+}
+
+// BeginsWith matches when the string or binary attribute at path starts with
+// prefix.
+func (CondBuilder) BeginsWith(path string, prefix interface{}) Condition {
+	return function("begins_with", path, prefix)
+}
+
+// Contains matches when the string attribute at path contains the substring
+// v, or when the set or list at path contains the element v.
+func (CondBuilder) Contains(path string, v interface{}) Condition {
+	return function("contains", path, v)
+}
+
+// AttributeExists matches when the item has an attribute at path.
+func (CondBuilder) AttributeExists(path string) Condition {
+	return function("attribute_exists", path)
+}
+
+// AttributeNotExists matches when the item has no attribute at path. On an
+// item's key attribute, it matches only when the item does not exist yet.
+func (CondBuilder) AttributeNotExists(path string) Condition {
+	return function("attribute_not_exists", path)
+}
+
+// AttributeType matches when the attribute at path has the type, which is one
+// of the dynamodb.ScalarAttributeType values, or "SS", "NS", "BS", "M", "L",
+// "BOOL" or "NULL".
+func (CondBuilder) AttributeType(path, typ string) Condition {
+	return function("attribute_type", path, typ)
+}
+
+// join combines conditions with a logical operator, parenthesizing each one.
+func join(op string, conds []Condition) Condition {
+	return Condition{func(b *exprBuilder) (string, error) {
+		exprs := make([]string, len(conds))
+		for i, c := range conds {
+			expr, err := c.expr(b)
+			if err != nil {
+				return "", err
+			}
+			exprs[i] = "(" + expr + ")"
+		}
+		return strings.Join(exprs, " "+op+" "), nil
+	}}
+}
+
+// And matches when this condition and all of the others match.
+func (c Condition) And(others ...Condition) Condition {
+	return join("AND", append([]Condition{c}, others...))
+}
+
+// Or matches when this condition or any of the others match.
+func (c Condition) Or(others ...Condition) Condition {
+	return join("OR", append([]Condition{c}, others...))
+}
+
+// Not matches when this condition does not.
+func (c Condition) Not() Condition {
+	return Condition{func(b *exprBuilder) (string, error) {
+		expr, err := c.expr(b)
+		if err != nil {
+			return "", err
+		}
+		return "NOT (" + expr + ")", nil
+	}}
+}
+
+// expr builds the condition with a shared builder.
+func (c Condition) expr(b *exprBuilder) (string, error) {
+	if c.build == nil {
+		return "", errors.New("dynamis: empty condition")
+	}
+	return c.build(b)
+}
+
+// Build returns the condition expression along with the maps to use as its
+// ExpressionAttributeNames and ExpressionAttributeValues. A map is nil if it
+// would be empty, as DynamoDB rejects empty maps.
+func (c Condition) Build() (string, map[string]*string, map[string]*dynamodb.AttributeValue, error) {
+	b := newExprBuilder(DefaultCodecs)
+	expr, err := c.expr(b)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return expr, b.attrNames(), b.attrValues(), nil
+}
@@ -0,0 +1,177 @@
+package dynamis
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestCondition(t *testing.T) {
+	tests := []struct {
+		cond   Condition
+		expr   string
+		names  map[string]*string
+		values map[string]*dynamodb.AttributeValue
+	}{
+		{
+			cond:  Cond.Eq("version", 3),
+			expr:  "#n0 = :v0",
+			names: map[string]*string{"#n0": aws.String("version")},
+			values: map[string]*dynamodb.AttributeValue{
+				":v0": {N: aws.String("3")},
+			},
+		},
+		{
+			cond: Cond.Ne("name", "x"),
+			expr: "#n0 <> :v0",
+		},
+		{
+			cond: Cond.Lt("n", 1),
+			expr: "#n0 < :v0",
+		},
+		{
+			cond: Cond.Le("n", 1),
+			expr: "#n0 <= :v0",
+		},
+		{
+			cond: Cond.Gt("n", 1),
+			expr: "#n0 > :v0",
+		},
+		{
+			cond: Cond.Ge("n", 1),
+			expr: "#n0 >= :v0",
+		},
+		{
+			cond: Cond.Between("n", 1, 9),
+			expr: "#n0 BETWEEN :v0 AND :v1",
+		},
+		{
+			cond: Cond.In("color", "red", "blue"),
+			expr: "#n0 IN (:v0, :v1)",
+		},
+		{
+			cond: Cond.BeginsWith("name", "R"),
+			expr: "begins_with(#n0, :v0)",
+		},
+		{
+			cond: Cond.Contains("tags", "go"),
+			expr: "contains(#n0, :v0)",
+		},
+		{
+			cond:  Cond.AttributeExists("address.city"),
+			expr:  "attribute_exists(#n0.#n1)",
+			names: map[string]*string{"#n0": aws.String("address"), "#n1": aws.String("city")},
+		},
+		{
+			cond: Cond.AttributeNotExists("events[0]"),
+			expr: "attribute_not_exists(#n0[0])",
+		},
+		{
+			cond: Cond.AttributeType("n", dynamodb.ScalarAttributeTypeN),
+			expr: "attribute_type(#n0, :v0)",
+			values: map[string]*dynamodb.AttributeValue{
+				":v0": {S: aws.String("N")},
+			},
+		},
+		{
+			// Combined conditions share placeholders.
+			cond: Cond.AttributeNotExists("user_id").Or(Cond.Eq("version", 3), Cond.Eq("user_id", "u1")),
+			expr: "(attribute_not_exists(#n0)) OR (#n1 = :v0) OR (#n0 = :v1)",
+			names: map[string]*string{
+				"#n0": aws.String("user_id"),
+				"#n1": aws.String("version"),
+			},
+			values: map[string]*dynamodb.AttributeValue{
+				":v0": {N: aws.String("3")},
+				":v1": {S: aws.String("u1")},
+			},
+		},
+		{
+			cond: Cond.Gt("n", 1).And(Cond.Lt("n", 9)).Not(),
+			expr: "NOT ((#n0 > :v0) AND (#n0 < :v1))",
+		},
+	}
+	for i, test := range tests {
+		expr, names, values, err := test.cond.Build()
+		if err != nil {
+			t.Errorf("%d Build() error %s", i, err)
+			continue
+		}
+		if expr != test.expr {
+			t.Errorf("%d Build() got %q, want %q", i, expr, test.expr)
+		}
+		if test.names != nil && !reflect.DeepEqual(names, test.names) {
+			t.Errorf("%d Build() names got %#v, want %#v", i, names, test.names)
+		}
+		if test.values != nil && !reflect.DeepEqual(values, test.values) {
+			t.Errorf("%d Build() values got %#v, want %#v", i, values, test.values)
+		}
+	}
+}
+
+func TestConditionNoValues(t *testing.T) {
+	// DynamoDB rejects empty placeholder maps.
+	_, _, values, err := Cond.AttributeExists("id").Build()
+	if err != nil || values != nil {
+		t.Errorf("Build() got (%#v, %v), want nil values", values, err)
+	}
+}
+
+func TestConditionErrors(t *testing.T) {
+	tests := []Condition{
+		{},
+		Cond.Eq("name", ""),
+		Cond.Eq("", "x"),
+		Cond.In("n"),
+		Cond.Between("n", 1, nil),
+		Cond.Eq("n", 1).And(Condition{}),
+		Condition{}.Not(),
+	}
+	for i, cond := range tests {
+		if _, _, _, err := cond.Build(); err == nil {
+			t.Errorf("%d Build() want error", i)
+		}
+	}
+}
+
+func TestTableConditionalWrites(t *testing.T) {
+	tbl := newTable()
+	if err := createStrTable(tbl); err != nil {
+		t.Fatalf("Failed initializing: %s", err)
+	}
+	table := CheckTable(tbl.db, tbl.name)
+	key := func(w ValueWriter) {
+		w.Str("str", "k1")
+	}
+	item := func(version int) func(ValueWriter) {
+		return func(w ValueWriter) {
+			key(w)
+			w.Int("version", version)
+		}
+	}
+	create := Cond.AttributeNotExists("str")
+
+	if err := table.PutIf(create, item(1)); err != nil {
+		t.Fatalf("PutIf(create) error %s", err)
+	}
+	if err := table.PutIf(create, item(1)); err != ErrConditionFailed {
+		t.Errorf("PutIf(create) again got %v, want %v", err, ErrConditionFailed)
+	}
+	if err := table.PutIf(Cond.Eq("version", 1), item(2)); err != nil {
+		t.Errorf("PutIf(version) error %s", err)
+	}
+	if err := table.DeleteIf(Cond.Eq("version", 1), key); err != ErrConditionFailed {
+		t.Errorf("DeleteIf(stale) got %v, want %v", err, ErrConditionFailed)
+	}
+	if err := table.DeleteIf(Cond.Eq("version", 2), key); err != nil {
+		t.Errorf("DeleteIf() error %s", err)
+	}
+	if got := table.RowCount(); got != 0 {
+		t.Errorf("RowCount() got %d, want 0", got)
+	}
+	if err := table.PutIf(Condition{}, item(1)); err == nil {
+		t.Errorf("PutIf(empty) want error")
+	}
+}
@@ -0,0 +1,59 @@
+package dynamis
+
+import "errors"
+
+var (
+	// ErrMissing is reported when an item does not contain a key.
+	ErrMissing = errors.New("missing value")
+
+	// ErrNull is reported when a value is an explicit NULL.
+	ErrNull = errors.New("null value")
+
+	// ErrType is reported when a value exists but has a different type than
+	// the one being read.
+	ErrType = errors.New("wrong type")
+
+	// ErrUndefined is reported when a custom value has no definition.
+	ErrUndefined = errors.New("no definition")
+
+	// ErrRange is reported when a number is outside the range expected for
+	// the value being read, such as Unix milliseconds read as seconds.
+	ErrRange = errors.New("out of range")
+
+	// ErrEmptyKey is returned when a key function writes no attributes, such
+	// as when every key value is empty and skipped.
+	ErrEmptyKey = errors.New("dynamis: key is empty")
+
+	// ErrEmptyItem is returned when an item function writes no attributes.
+	ErrEmptyItem = errors.New("dynamis: item is empty")
+
+	// ErrNotFound is returned by Table.Get when there is no item with the key.
+	ErrNotFound = errors.New("dynamis: item not found")
+
+	// ErrConditionFailed is returned when a write's condition does not match.
+	ErrConditionFailed = errors.New("dynamis: condition failed")
+
+	// ErrVersionConflict is returned by versioned writes when the item has
+	// changed since its version was read.
+	ErrVersionConflict = errors.New("dynamis: version conflict")
+)
+
+// ValueError describes why a value could not be read from an item.
+type ValueError struct {
+	// Path is the key that was read. For nested readers it is the full
+	// document path, such as "address.city" or "events[3].type".
+	Path string
+
+	// Err is ErrMissing, ErrNull, ErrType, ErrUndefined, ErrRange, or the
+	// error from parsing or converting the value.
+	Err error
+}
+
+func (e *ValueError) Error() string {
+	return "dynamis: " + e.Path + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *ValueError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,13 @@
+package dynamis
+
+import "testing"
+
+func TestValueError(t *testing.T) {
+	err := &ValueError{"address.city", ErrMissing}
+	if got, want := err.Error(), "dynamis: address.city: missing value"; got != want {
+		t.Errorf("Error() got %q, want %q", got, want)
+	}
+	if got := err.Unwrap(); got != ErrMissing {
+		t.Errorf("Unwrap() got %#v, want %#v", got, ErrMissing)
+	}
+}
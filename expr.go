@@ -0,0 +1,118 @@
+package dynamis
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// exprBuilder collects the attribute names and values used by an expression.
+// Every name is replaced by a placeholder such as #n0, so reserved words can
+// be used as attribute names, and every value by a placeholder such as :v0.
+// A single builder is shared by all of the expressions in one request so that
+// the placeholders don't collide.
+type exprBuilder struct {
+	codecs *Codecs
+	names  map[string]*string
+	values map[string]*dynamodb.AttributeValue
+	byName map[string]string
+}
+
+func newExprBuilder(codecs *Codecs) *exprBuilder {
+	return &exprBuilder{
+		codecs: codecs,
+		names:  make(map[string]*string),
+		values: make(map[string]*dynamodb.AttributeValue),
+		byName: make(map[string]string),
+	}
+}
+
+// name returns the expression for a document path such as "address.city" or
+// "events[3].type", with a placeholder for each map key. The same key always
+// gets the same placeholder.
+func (b *exprBuilder) name(path string) (string, error) {
+	elems, err := parsePath(path)
+	if err != nil {
+		return "", err
+	}
+	var expr strings.Builder
+	for _, e := range elems {
+		if e.isIndex {
+			expr.WriteString(indexPath("", e.index))
+			continue
+		}
+		if expr.Len() > 0 {
+			expr.WriteByte('.')
+		}
+		expr.WriteString(b.attr(e.name))
+	}
+	return expr.String(), nil
+}
+
+// attr returns the placeholder for a top-level attribute name, which is used
+// as it is, even if it contains dots or brackets.
+func (b *exprBuilder) attr(name string) string {
+	p, ok := b.byName[name]
+	if !ok {
+		p = "#n" + strconv.Itoa(len(b.byName))
+		b.byName[name] = p
+		b.names[p] = &name
+	}
+	return p
+}
+
+// value encodes v with the builder's codecs and returns its placeholder.
+// Values that encode to nothing, such as empty strings, can't be used in an
+// expression, and are reported as ErrMissing at path.
+func (b *exprBuilder) value(path string, v interface{}) (string, error) {
+	p, ok, err := b.encode(path, v, false)
+	if err == nil && !ok {
+		err = &ValueError{path, ErrMissing}
+	}
+	return p, err
+}
+
+// encode encodes v and returns its placeholder, or false if v encodes to
+// nothing. If set is true, slices are encoded as sets rather than lists.
+func (b *exprBuilder) encode(path string, v interface{}, set bool) (string, bool, error) {
+	if v == nil {
+		return "", false, nil
+	}
+	p := ":v" + strconv.Itoa(len(b.values))
+	item := make(map[string]*dynamodb.AttributeValue)
+	if err := b.codecs.marshalValue(item, p, reflect.ValueOf(v), set, path); err != nil {
+		return "", false, err
+	}
+	val, ok := item[p]
+	if !ok {
+		return "", false, nil
+	}
+	b.values[p] = val
+	return p, true, nil
+}
+
+// raw returns the placeholder for an attribute value that is used as it is.
+func (b *exprBuilder) raw(val *dynamodb.AttributeValue) string {
+	p := ":v" + strconv.Itoa(len(b.values))
+	b.values[p] = val
+	return p
+}
+
+// attrNames returns the placeholders for names, or nil if there are none, as
+// DynamoDB rejects an empty map.
+func (b *exprBuilder) attrNames() map[string]*string {
+	if len(b.names) == 0 {
+		return nil
+	}
+	return b.names
+}
+
+// attrValues returns the placeholders for values, or nil if there are none.
+func (b *exprBuilder) attrValues() map[string]*dynamodb.AttributeValue {
+	if len(b.values) == 0 {
+		return nil
+	}
+	return b.values
+}
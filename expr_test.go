@@ -0,0 +1,84 @@
+package dynamis
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestExprBuilderName(t *testing.T) {
+	b := newExprBuilder(DefaultCodecs)
+	tests := []struct {
+		path string
+		want string
+		err  bool
+	}{
+		{path: "id", want: "#n0"},
+		{path: "address.city", want: "#n1.#n2"},
+		{path: "events[3].id", want: "#n3[3].#n0"},
+		{path: "address", want: "#n1"},
+		{path: "", err: true},
+		{path: "a[x]", err: true},
+	}
+	for i, test := range tests {
+		got, err := b.name(test.path)
+		if (err != nil) != test.err {
+			t.Errorf("%d name(%q) error %v", i, test.path, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%d name(%q) got %q, want %q", i, test.path, got, test.want)
+		}
+	}
+	want := map[string]*string{
+		"#n0": aws.String("id"),
+		"#n1": aws.String("address"),
+		"#n2": aws.String("city"),
+		"#n3": aws.String("events"),
+	}
+	if got := b.attrNames(); !reflect.DeepEqual(got, want) {
+		t.Errorf("attrNames() got %#v, want %#v", got, want)
+	}
+}
+
+func TestExprBuilderValue(t *testing.T) {
+	b := newExprBuilder(DefaultCodecs)
+	if b.attrNames() != nil || b.attrValues() != nil {
+		t.Errorf("empty builder want nil maps")
+	}
+	tests := []struct {
+		v    interface{}
+		want string
+		err  error
+	}{
+		{v: "a", want: ":v0"},
+		{v: 7, want: ":v1"},
+		{v: time.Minute, want: ":v2"},
+		{v: "", err: ErrMissing},
+		{v: "a", want: ":v3"},
+	}
+	for i, test := range tests {
+		got, err := b.value("k", test.v)
+		if test.err != nil {
+			if verr, ok := err.(*ValueError); !ok || verr.Path != "k" || verr.Err != test.err {
+				t.Errorf("%d value(%#v) got %#v, want %v", i, test.v, err, test.err)
+			}
+			continue
+		}
+		if err != nil || got != test.want {
+			t.Errorf("%d value(%#v) got (%q, %v), want %q", i, test.v, got, err, test.want)
+		}
+	}
+	want := map[string]*dynamodb.AttributeValue{
+		":v0": {S: aws.String("a")},
+		":v1": {N: aws.String("7")},
+		":v2": {N: aws.String("60000000000")},
+		":v3": {S: aws.String("a")},
+	}
+	if got := b.attrValues(); !reflect.DeepEqual(got, want) {
+		t.Errorf("attrValues() got %#v, want %#v", got, want)
+	}
+}
@@ -0,0 +1,36 @@
+package dynamis
+
+// Filter returns a wrapper over the same table that only sees rows matching
+// the condition in RowCount, Rows, EachRow and Query. Filtering a filtered
+// table matches rows that meet both conditions. Get, Put and the other item
+// operations are not affected.
+//
+// DynamoDB applies filters after reading each page, so a filtered scan reads
+// as much of the table as an unfiltered one.
+func (t Table) Filter(cond Condition) Table {
+	if t.filter != nil {
+		cond = t.filter.And(cond)
+	}
+	t.filter = &cond
+	return t
+}
+
+// Filter returns a wrapper over the same index that only sees rows matching
+// the condition in RowCount, Rows, EachRow and Query.
+func (i Index) Filter(cond Condition) Index {
+	i.table = i.table.Filter(cond)
+	return i
+}
+
+// filterExpr builds the filter with a shared builder, returning nil if there
+// is no filter.
+func (t Table) filterExpr(b *exprBuilder) (*string, error) {
+	if t.filter == nil {
+		return nil, nil
+	}
+	expr, err := t.filter.expr(b)
+	if err != nil {
+		return nil, err
+	}
+	return &expr, nil
+}
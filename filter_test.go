@@ -0,0 +1,89 @@
+package dynamis
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+func TestFilterInputs(t *testing.T) {
+	table := Table{tableName: "users"}.Filter(Cond.Eq("status", "active"))
+
+	scan, err := table.scanInput()
+	if err != nil {
+		t.Fatalf("scanInput() error %s", err)
+	}
+	if got, want := aws.StringValue(scan.FilterExpression), "#n0 = :v0"; got != want {
+		t.Errorf("scanInput() filter got %q, want %q", got, want)
+	}
+	if len(scan.ExpressionAttributeNames) != 1 || len(scan.ExpressionAttributeValues) != 1 {
+		t.Errorf("scanInput() got %#v", scan)
+	}
+
+	// Counts are filtered too.
+	count, err := table.Project(Project("name")).countInput()
+	if err != nil || aws.StringValue(count.FilterExpression) != "#n0 = :v0" || count.ProjectionExpression != nil {
+		t.Errorf("countInput() got (%#v, %v)", count, err)
+	}
+
+	// Filters combine, and share placeholders with the key condition and
+	// projection.
+	query, err := table.Filter(Cond.Gt("age", 21)).Project(Project("status")).queryInput(Key("id").Eq("u1"), nil)
+	if err != nil {
+		t.Fatalf("queryInput() error %s", err)
+	}
+	if got, want := aws.StringValue(query.KeyConditionExpression), "#n0 = :v0"; got != want {
+		t.Errorf("queryInput() key got %q, want %q", got, want)
+	}
+	if got, want := aws.StringValue(query.ProjectionExpression), "#n1"; got != want {
+		t.Errorf("queryInput() projection got %q, want %q", got, want)
+	}
+	if got, want := aws.StringValue(query.FilterExpression), "(#n1 = :v1) AND (#n2 > :v2)"; got != want {
+		t.Errorf("queryInput() filter got %q, want %q", got, want)
+	}
+
+	// The original table is unchanged.
+	scan, _ = Table{tableName: "users"}.scanInput()
+	if scan.FilterExpression != nil || scan.ExpressionAttributeValues != nil {
+		t.Errorf("scanInput() unfiltered got %#v", scan)
+	}
+
+	if _, err := table.Filter(Cond.Eq("status", "")).scanInput(); err == nil {
+		t.Errorf("scanInput(bad filter) want error")
+	}
+}
+
+func TestTableFilter(t *testing.T) {
+	tbl := newTable()
+	if err := createQueryTable(tbl, 6); err != nil {
+		t.Fatalf("Failed initializing: %s", err)
+	}
+	table := CheckTable(tbl.db, tbl.name)
+	even := table.Filter(Cond.In("n", 0, 2, 4))
+
+	if got, want := even.RowCount(), 6; got != want {
+		t.Errorf("RowCount() got %d, want %d", got, want)
+	}
+	if got, want := even.Filter(Cond.Eq("user", "a")).RowCount(), 3; got != want {
+		t.Errorf("RowCount(a) got %d, want %d", got, want)
+	}
+	if got, want := even.ParallelRowCount(3), 6; got != want {
+		t.Errorf("ParallelRowCount() got %d, want %d", got, want)
+	}
+
+	rows, _, err := even.RowsE()
+	if err != nil || len(rows) != 6 {
+		t.Errorf("RowsE() got (%d, %v), want 6", len(rows), err)
+	}
+
+	rows, _, err = even.QueryE(Key("user").Eq("b"), Key("n").Ge(1))
+	var got []int
+	for _, row := range rows {
+		got = append(got, row.Int("n"))
+	}
+	sort.Ints(got)
+	if err != nil || len(got) != 2 || got[0] != 2 || got[1] != 4 {
+		t.Errorf("QueryE() got (%#v, %v), want [2 4]", got, err)
+	}
+}
@@ -0,0 +1,131 @@
+package dynamis
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// Index is a convenient wrapper over a global or local secondary index of a
+// table. It reads only the attributes projected into the index, and a sparse
+// index holds only the items that have its key attributes.
+type Index struct {
+	table Table
+	name  string
+}
+
+// Index initializes a wrapper over the named secondary index of the table.
+func (t Table) Index(name string) Index {
+	return Index{t, name}
+}
+
+// scanInput returns the input to scan the whole index.
+func (i Index) scanInput() (*dynamodb.ScanInput, error) {
+	input, err := i.table.scanInput()
+	if err != nil {
+		return nil, err
+	}
+	input.IndexName = aws.String(i.name)
+	return input, nil
+}
+
+// countInput returns the input to count the rows in the index.
+func (i Index) countInput() (*dynamodb.ScanInput, error) {
+	input, err := i.table.countInput()
+	if err != nil {
+		return nil, err
+	}
+	input.IndexName = aws.String(i.name)
+	return input, nil
+}
+
+// RowCount returns the number of rows in the index. It returns -1 if an error
+// occurs.
+func (i Index) RowCount() int {
+	n, err := i.RowCountE()
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// RowCountE returns the number of rows in the index, or the error from
+// DynamoDB.
+func (i Index) RowCountE() (int, error) {
+	input, err := i.countInput()
+	if err != nil {
+		return 0, err
+	}
+	return countRows(i.table.db, input)
+}
+
+// ParallelRowCount returns the number of rows in the index, scanning the index
+// in segments concurrently. It returns -1 if an error occurs.
+func (i Index) ParallelRowCount(segments int) int {
+	n, err := i.ParallelRowCountE(segments)
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// ParallelRowCountE is like ParallelRowCount, but returns the error from
+// DynamoDB.
+func (i Index) ParallelRowCountE(segments int) (int, error) {
+	input, err := i.countInput()
+	if err != nil {
+		return 0, err
+	}
+	return countRowsParallel(i.table.db, *input, segments)
+}
+
+// Rows returns a simple accessor for each row in the index. The rows are
+// returned in no particular order. If an error occurs, the rows will be nil.
+func (i Index) Rows() ([]Row, ValueDefiner) {
+	rows, vd, err := i.RowsE()
+	if err != nil {
+		return nil, nil
+	}
+	return rows, vd
+}
+
+// RowsE is like Rows, but returns the error from DynamoDB.
+func (i Index) RowsE() ([]Row, ValueDefiner, error) {
+	input, err := i.scanInput()
+	if err != nil {
+		return nil, nil, err
+	}
+	return scanAll(i.table.db, input)
+}
+
+// EachRow calls f with each row in the index, one page of the scan at a time.
+// It works like Table.EachRow.
+func (i Index) EachRow(f func(Row) error) error {
+	input, err := i.scanInput()
+	if err != nil {
+		return err
+	}
+	return scanRows(i.table.db, input, newValueDefiner(), f)
+}
+
+// Query returns a Row accessor for every item in the index with the partition
+// key matched by pk, optionally narrowed by one condition on the sort key. The
+// keys are those of the index, not the table. If an error occurs, the rows
+// will be nil.
+func (i Index) Query(pk KeyCondition, sk ...KeyCondition) ([]Row, ValueDefiner) {
+	rows, vd, err := i.QueryE(pk, sk...)
+	if err != nil {
+		return nil, nil
+	}
+	return rows, vd
+}
+
+// QueryE is like Query, but returns the error from building the query or from
+// DynamoDB.
+func (i Index) QueryE(pk KeyCondition, sk ...KeyCondition) ([]Row, ValueDefiner, error) {
+	input, err := i.table.queryInput(pk, sk)
+	if err != nil {
+		return nil, nil, err
+	}
+	input.IndexName = aws.String(i.name)
+	return queryAll(i.table.db, input)
+}
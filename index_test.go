@@ -0,0 +1,141 @@
+package dynamis
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// createIndexTable creates a table keyed by "id" with a sparse, keys-only
+// index "by_team" keyed by "team" and "id". Rows with an empty team are not in
+// the index.
+func createIndexTable(t table, teams map[string]string) error {
+	_, err := t.db.CreateTable(&dynamodb.CreateTableInput{
+		TableName: aws.String(t.name),
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{
+				AttributeName: aws.String("id"),
+				AttributeType: aws.String("S"),
+			},
+			{
+				AttributeName: aws.String("team"),
+				AttributeType: aws.String("S"),
+			},
+		},
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{
+				AttributeName: aws.String("id"),
+				KeyType:       aws.String("HASH"),
+			},
+		},
+		GlobalSecondaryIndexes: []*dynamodb.GlobalSecondaryIndex{
+			{
+				IndexName: aws.String("by_team"),
+				KeySchema: []*dynamodb.KeySchemaElement{
+					{
+						AttributeName: aws.String("team"),
+						KeyType:       aws.String("HASH"),
+					},
+					{
+						AttributeName: aws.String("id"),
+						KeyType:       aws.String("RANGE"),
+					},
+				},
+				Projection: &dynamodb.Projection{
+					ProjectionType: aws.String(dynamodb.ProjectionTypeKeysOnly),
+				},
+				ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+					ReadCapacityUnits:  aws.Int64(1),
+					WriteCapacityUnits: aws.Int64(1),
+				},
+			},
+		},
+		ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+			ReadCapacityUnits:  aws.Int64(1),
+			WriteCapacityUnits: aws.Int64(1),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	for id, team := range teams {
+		item := make(map[string]*dynamodb.AttributeValue)
+		w := NewValueWriter(item)
+		w.Str("id", id)
+		w.Str("team", team)
+		w.Str("name", "name-"+id)
+		_, err = t.db.PutItem(&dynamodb.PutItemInput{
+			TableName: aws.String(t.name),
+			Item:      item,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestTableIndex(t *testing.T) {
+	tbl := newTable()
+	err := createIndexTable(tbl, map[string]string{
+		"1": "red",
+		"2": "blue",
+		"3": "red",
+		"4": "",
+	})
+	if err != nil {
+		t.Fatalf("Failed initializing: %s", err)
+	}
+	table := CheckTable(tbl.db, tbl.name)
+	index := table.Index("by_team")
+
+	// The sparse index skips rows without a team.
+	if got, want := table.RowCount(), 4; got != want {
+		t.Errorf("Table.RowCount() got %d, want %d", got, want)
+	}
+	if got, want := index.RowCount(), 3; got != want {
+		t.Errorf("RowCount() got %d, want %d", got, want)
+	}
+	if got, want := index.ParallelRowCount(2), 3; got != want {
+		t.Errorf("ParallelRowCount() got %d, want %d", got, want)
+	}
+
+	// Only keys are projected.
+	rows, vd := index.Rows()
+	if vd == nil {
+		t.Fatalf("Rows() got nil ValueDefiner")
+	}
+	var ids []string
+	for _, row := range rows {
+		if row.Has("name") {
+			t.Errorf("Rows() got unprojected name in %#v", row)
+		}
+		ids = append(ids, row.Str("id"))
+	}
+	sort.Strings(ids)
+	if want := []string{"1", "2", "3"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("Rows() got %#v, want %#v", ids, want)
+	}
+
+	n := 0
+	if err := index.EachRow(func(Row) error { n++; return nil }); err != nil || n != 3 {
+		t.Errorf("EachRow() got (%d, %v), want 3", n, err)
+	}
+
+	rows, _, err = index.QueryE(Key("team").Eq("red"), Key("id").Gt("1"))
+	if err != nil || len(rows) != 1 || rows[0].Str("id") != "3" {
+		t.Errorf("QueryE() got (%#v, %v)", rows, err)
+	}
+
+	// Missing indexes are errors.
+	missing := table.Index("missing")
+	if got, want := missing.RowCount(), -1; got != want {
+		t.Errorf("RowCount(missing) got %d, want %d", got, want)
+	}
+	if rows, vd := missing.Query(Key("team").Eq("red")); rows != nil || vd != nil {
+		t.Errorf("Query(missing) got (%#v, %#v)", rows, vd)
+	}
+}
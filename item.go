@@ -0,0 +1,145 @@
+package dynamis
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// writeItem returns the attributes written by f.
+func writeItem(f func(ValueWriter)) map[string]*dynamodb.AttributeValue {
+	item := make(map[string]*dynamodb.AttributeValue)
+	f(NewValueWriter(item))
+	return item
+}
+
+// buildItem returns the item written by f, or ErrEmptyItem if it wrote
+// nothing.
+func buildItem(f func(ValueWriter)) (map[string]*dynamodb.AttributeValue, error) {
+	item := writeItem(f)
+	if len(item) == 0 {
+		return nil, ErrEmptyItem
+	}
+	return item, nil
+}
+
+// buildKey returns the key written by f, or ErrEmptyKey if it wrote nothing.
+func buildKey(f func(ValueWriter)) (map[string]*dynamodb.AttributeValue, error) {
+	key := writeItem(f)
+	if len(key) == 0 {
+		return nil, ErrEmptyKey
+	}
+	return key, nil
+}
+
+// Get reads the item with the key written by the function. The key follows the
+// ValueWriter rules, so an empty value is skipped rather than sent. If there is
+// no such item, Get returns an empty reader and ErrNotFound. The reader is
+// never nil, so it can be used even when there is an error.
+func (t Table) Get(key func(ValueWriter)) (ValueReader, error) {
+	k, err := buildKey(key)
+	if err != nil {
+		return NewValueReader(nil), err
+	}
+	b := newExprBuilder(DefaultCodecs)
+	projection, err := t.projection.apply(b)
+	if err != nil {
+		return NewValueReader(nil), err
+	}
+	resp, err := t.db.GetItem(&dynamodb.GetItemInput{
+		TableName:                aws.String(t.tableName),
+		Key:                      k,
+		ProjectionExpression:     projection,
+		ExpressionAttributeNames: b.attrNames(),
+	})
+	if err != nil {
+		return NewValueReader(nil), err
+	}
+	if resp.Item == nil {
+		return NewValueReader(nil), ErrNotFound
+	}
+	return NewValueReader(resp.Item), nil
+}
+
+// Put writes the item built by the function, replacing any item with the same
+// key. It returns ErrEmptyItem if the function writes nothing.
+func (t Table) Put(item func(ValueWriter)) error {
+	i, err := buildItem(item)
+	if err != nil {
+		return err
+	}
+	_, err = t.db.PutItem(&dynamodb.PutItemInput{
+		TableName: aws.String(t.tableName),
+		Item:      i,
+	})
+	return err
+}
+
+// PutIf is like Put, but writes the item only if the existing item matches
+// the condition. It returns ErrConditionFailed if it does not.
+func (t Table) PutIf(cond Condition, item func(ValueWriter)) error {
+	i, err := buildItem(item)
+	if err != nil {
+		return err
+	}
+	return t.putIf(cond, i)
+}
+
+func (t Table) putIf(cond Condition, item map[string]*dynamodb.AttributeValue) error {
+	expr, names, values, err := cond.Build()
+	if err != nil {
+		return err
+	}
+	_, err = t.db.PutItem(&dynamodb.PutItemInput{
+		TableName:                 aws.String(t.tableName),
+		Item:                      item,
+		ConditionExpression:       aws.String(expr),
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
+	})
+	return conditionErr(err)
+}
+
+// Delete removes the item with the key written by the function. Deleting an
+// item that does not exist is not an error.
+func (t Table) Delete(key func(ValueWriter)) error {
+	k, err := buildKey(key)
+	if err != nil {
+		return err
+	}
+	_, err = t.db.DeleteItem(&dynamodb.DeleteItemInput{
+		TableName: aws.String(t.tableName),
+		Key:       k,
+	})
+	return err
+}
+
+// DeleteIf is like Delete, but removes the item only if it matches the
+// condition. It returns ErrConditionFailed if it does not.
+func (t Table) DeleteIf(cond Condition, key func(ValueWriter)) error {
+	k, err := buildKey(key)
+	if err != nil {
+		return err
+	}
+	expr, names, values, err := cond.Build()
+	if err != nil {
+		return err
+	}
+	_, err = t.db.DeleteItem(&dynamodb.DeleteItemInput{
+		TableName:                 aws.String(t.tableName),
+		Key:                       k,
+		ConditionExpression:       aws.String(expr),
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
+	})
+	return conditionErr(err)
+}
+
+// conditionErr turns DynamoDB's conditional check failure into
+// ErrConditionFailed, and returns any other error as it is.
+func conditionErr(err error) error {
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+		return ErrConditionFailed
+	}
+	return err
+}
@@ -0,0 +1,103 @@
+package dynamis
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestBuildKey(t *testing.T) {
+	tests := []struct {
+		key  func(ValueWriter)
+		want map[string]*dynamodb.AttributeValue
+		err  error
+	}{
+		{
+			key: func(w ValueWriter) {
+				w.Str("user", "u1")
+				w.Int("n", 0)
+			},
+			want: map[string]*dynamodb.AttributeValue{
+				"user": {S: aws.String("u1")},
+				"n":    {N: aws.String("0")},
+			},
+		},
+		{
+			// Empty values are skipped, leaving no key.
+			key: func(w ValueWriter) {
+				w.Str("user", "")
+			},
+			err: ErrEmptyKey,
+		},
+	}
+	for i, test := range tests {
+		got, err := buildKey(test.key)
+		if err != test.err {
+			t.Errorf("%d buildKey() error got %v, want %v", i, err, test.err)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%d buildKey() got %#v, want %#v", i, got, test.want)
+		}
+	}
+	// Items report that the item, rather than the key, is empty.
+	if _, err := buildItem(func(w ValueWriter) { w.Str("user", "") }); err != ErrEmptyItem {
+		t.Errorf("buildItem(empty) got %v, want %v", err, ErrEmptyItem)
+	}
+}
+
+func TestTableItems(t *testing.T) {
+	tbl := newTable()
+	if err := createStrTable(tbl); err != nil {
+		t.Fatalf("Failed initializing: %s", err)
+	}
+	table := CheckTable(tbl.db, tbl.name)
+	key := func(w ValueWriter) {
+		w.Str("str", "k1")
+	}
+
+	err := table.Put(func(w ValueWriter) {
+		w.Str("str", "k1")
+		w.Str("name", "Ryan")
+		w.Str("empty", "")
+	})
+	if err != nil {
+		t.Fatalf("Put() error %s", err)
+	}
+	r, err := table.Get(key)
+	if err != nil {
+		t.Fatalf("Get() error %s", err)
+	}
+	if got, want := r.Str("name"), "Ryan"; got != want {
+		t.Errorf("Get() name got %#v, want %#v", got, want)
+	}
+	if r.Has("empty") {
+		t.Errorf("Get() got empty value")
+	}
+
+	if err := table.Delete(key); err != nil {
+		t.Fatalf("Delete() error %s", err)
+	}
+	r, err = table.Get(key)
+	if err != ErrNotFound || r == nil || r.Str("name") != "" {
+		t.Errorf("Get() after Delete() got (%#v, %v)", r, err)
+	}
+
+	// Deleting a missing item is fine, but an empty key is not.
+	if err := table.Delete(key); err != nil {
+		t.Errorf("Delete(missing) error %s", err)
+	}
+	empty := func(w ValueWriter) {
+		w.Str("str", "")
+	}
+	if err := table.Put(empty); err != ErrEmptyItem {
+		t.Errorf("Put(empty) got %v, want %v", err, ErrEmptyItem)
+	}
+	if err := table.Delete(empty); err != ErrEmptyKey {
+		t.Errorf("Delete(empty) got %v, want %v", err, ErrEmptyKey)
+	}
+	if r, err := table.Get(empty); err != ErrEmptyKey || r == nil {
+		t.Errorf("Get(empty) got (%#v, %v)", r, err)
+	}
+}
@@ -0,0 +1,529 @@
+package dynamis
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// Marshal converts a struct, or a map with string keys, into a DynamoDB item.
+//
+// Struct fields are stored under their name, or the name given by a `dynamis`
+// struct tag. The tag may also include these options:
+//
+//	dynamis:"-"               the field is never stored
+//	dynamis:"name,omitempty"  zero numbers and false bools are not stored
+//	dynamis:"tags,set"        a slice is stored as a SS, NS or BS set
+//
+// Values follow the same rules as the Set helpers: empty strings, empty
+// binary values, nil pointers and empty sets, lists and maps are never
+// stored. Nested structs and maps become map attributes, and other slices
+// become lists. Embedded structs without a tag have their fields stored as if
+// they were part of the outer struct. Embedded pointers to structs are not
+// flattened: like other pointer fields they are stored as a map attribute,
+// named for the type, or skipped if the type is unexported.
+//
+// Types with a registered Codec are converted by it. Marshal uses the
+// DefaultCodecs registry.
+func Marshal(v interface{}) (map[string]*dynamodb.AttributeValue, error) {
+	return DefaultCodecs.Marshal(v)
+}
+
+// Marshal converts a struct or map into a DynamoDB item like the package
+// function Marshal, using the codecs in this registry.
+func (c *Codecs) Marshal(v interface{}) (map[string]*dynamodb.AttributeValue, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	item := make(map[string]*dynamodb.AttributeValue)
+	switch rv.Kind() {
+	case reflect.Struct:
+		if err := c.marshalStruct(item, rv, ""); err != nil {
+			return nil, err
+		}
+	case reflect.Map:
+		if err := c.marshalMap(item, rv, ""); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("dynamis: cannot marshal %T, want struct or map", v)
+	}
+	return item, nil
+}
+
+// Unmarshal stores the values of a DynamoDB item into the struct or map
+// pointed to by v. It understands the same `dynamis` struct tags as Marshal.
+// Fields with no attribute in the item are left unchanged, and NULL attributes
+// set the field to its zero value. If a value has the wrong type or cannot be
+// parsed, Unmarshal returns a *ValueError holding its path. Unmarshal uses the
+// DefaultCodecs registry.
+func Unmarshal(item map[string]*dynamodb.AttributeValue, v interface{}) error {
+	return DefaultCodecs.Unmarshal(item, v)
+}
+
+// Unmarshal stores an item into a struct or map like the package function
+// Unmarshal, using the codecs in this registry.
+func (c *Codecs) Unmarshal(item map[string]*dynamodb.AttributeValue, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("dynamis: cannot unmarshal into %T, want non-nil pointer", v)
+	}
+	rv = rv.Elem()
+	switch rv.Kind() {
+	case reflect.Struct, reflect.Map:
+		if item == nil {
+			return nil
+		}
+		return c.unmarshalValue(&dynamodb.AttributeValue{M: item}, rv, "")
+	}
+	return fmt.Errorf("dynamis: cannot unmarshal into %T, want struct or map", v)
+}
+
+// field is a struct field that is stored as an attribute.
+type field struct {
+	name      string
+	index     []int
+	omitEmpty bool
+	set       bool
+}
+
+// structFields returns the stored fields of a struct type, including those of
+// embedded structs.
+func structFields(t reflect.Type) []field {
+	var fields []field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("dynamis")
+		if tag == "-" || (sf.PkgPath != "" && !sf.Anonymous) {
+			continue
+		}
+		opts := strings.Split(tag, ",")
+		if sf.Anonymous && opts[0] == "" && sf.Type.Kind() == reflect.Struct {
+			for _, f := range structFields(sf.Type) {
+				f.index = append([]int{i}, f.index...)
+				fields = append(fields, f)
+			}
+			continue
+		}
+		if sf.PkgPath != "" {
+			continue
+		}
+		f := field{name: opts[0], index: []int{i}}
+		if f.name == "" {
+			f.name = sf.Name
+		}
+		for _, o := range opts[1:] {
+			switch o {
+			case "omitempty":
+				f.omitEmpty = true
+			case "set":
+				f.set = true
+			}
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+func (c *Codecs) marshalStruct(item map[string]*dynamodb.AttributeValue, rv reflect.Value, path string) error {
+	for _, f := range structFields(rv.Type()) {
+		fv := rv.FieldByIndex(f.index)
+		if f.omitEmpty && isZeroScalar(fv) {
+			continue
+		}
+		if err := c.marshalValue(item, f.name, fv, f.set, joinPath(path, f.name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Codecs) marshalMap(item map[string]*dynamodb.AttributeValue, rv reflect.Value, path string) error {
+	if rv.Type().Key().Kind() != reflect.String {
+		return &ValueError{path, fmt.Errorf("cannot marshal map key type %s", rv.Type().Key())}
+	}
+	for _, k := range rv.MapKeys() {
+		key := k.String()
+		if err := c.marshalValue(item, key, rv.MapIndex(k), false, joinPath(path, key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// marshalValue stores rv in the item at key using the Set helpers, so a value
+// they would skip is not stored.
+func (c *Codecs) marshalValue(item map[string]*dynamodb.AttributeValue, key string, rv reflect.Value, set bool, path string) error {
+	if codec, ok := c.lookup(rv.Type()); ok && rv.CanInterface() {
+		val, err := codec.Encode(rv.Interface())
+		if err != nil {
+			return &ValueError{path, err}
+		}
+		if key != "" && val != nil {
+			item[key] = val
+		}
+		return nil
+	}
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return nil
+		}
+		return c.marshalValue(item, key, rv.Elem(), set, path)
+	case reflect.String:
+		SetStr(item, key, rv.String())
+	case reflect.Bool:
+		SetBool(item, key, rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		SetInt64(item, key, rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		SetUint64(item, key, rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		if _, err := formatFloat(rv.Float()); err != nil {
+			return &ValueError{path, err}
+		}
+		SetFloat64(item, key, rv.Float())
+	case reflect.Struct:
+		m := make(map[string]*dynamodb.AttributeValue)
+		if err := c.marshalStruct(m, rv, path); err != nil {
+			return err
+		}
+		SetMap(item, key, m)
+	case reflect.Map:
+		m := make(map[string]*dynamodb.AttributeValue)
+		if err := c.marshalMap(m, rv, path); err != nil {
+			return err
+		}
+		SetMap(item, key, m)
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			SetBytes(item, key, bytesOf(rv))
+			return nil
+		}
+		if set {
+			return c.marshalSet(item, key, rv, path)
+		}
+		var list []*dynamodb.AttributeValue
+		for i := 0; i < rv.Len(); i++ {
+			elem := make(map[string]*dynamodb.AttributeValue)
+			if err := c.marshalValue(elem, listKey, rv.Index(i), false, indexPath(path, i)); err != nil {
+				return err
+			}
+			list = append(list, elem[listKey])
+		}
+		SetList(item, key, list)
+	default:
+		return &ValueError{path, fmt.Errorf("cannot marshal type %s", rv.Type())}
+	}
+	return nil
+}
+
+// emptyValue returns what StoreEmpty stores for a value that marshalValue
+// skipped, or nil if DynamoDB can't store it.
+func (c *Codecs) emptyValue(rv reflect.Value) *dynamodb.AttributeValue {
+	for (rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface) && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return nil
+	}
+	if _, ok := c.lookup(rv.Type()); ok {
+		return nil
+	}
+	switch rv.Kind() {
+	case reflect.String:
+		return &dynamodb.AttributeValue{S: aws.String("")}
+	case reflect.Struct, reflect.Map:
+		return &dynamodb.AttributeValue{M: map[string]*dynamodb.AttributeValue{}}
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return &dynamodb.AttributeValue{B: []byte{}}
+		}
+		return &dynamodb.AttributeValue{L: []*dynamodb.AttributeValue{}}
+	}
+	return nil
+}
+
+// marshalSet stores a slice of strings, numbers or binary values as a set.
+func (c *Codecs) marshalSet(item map[string]*dynamodb.AttributeValue, key string, rv reflect.Value, path string) error {
+	switch elem := rv.Type().Elem(); {
+	case elem.Kind() == reflect.String:
+		ss := make([]string, rv.Len())
+		for i := range ss {
+			ss[i] = rv.Index(i).String()
+		}
+		SetStrSet(item, key, ss)
+	case isNumberKind(elem.Kind()):
+		var ns []string
+		for i := 0; i < rv.Len(); i++ {
+			n := make(map[string]*dynamodb.AttributeValue)
+			if err := c.marshalValue(n, listKey, rv.Index(i), false, indexPath(path, i)); err != nil {
+				return err
+			}
+			if val, ok := n[listKey]; ok {
+				ns = append(ns, *val.N)
+			}
+		}
+		if set := uniqueStrs(ns); key != "" && len(set) > 0 {
+			item[key] = &dynamodb.AttributeValue{NS: aws.StringSlice(set)}
+		}
+	case (elem.Kind() == reflect.Slice || elem.Kind() == reflect.Array) && elem.Elem().Kind() == reflect.Uint8:
+		bs := make([][]byte, rv.Len())
+		for i := range bs {
+			bs[i] = bytesOf(rv.Index(i))
+		}
+		SetBytesSet(item, key, bs)
+	default:
+		return &ValueError{path, fmt.Errorf("cannot marshal type %s as a set", rv.Type())}
+	}
+	return nil
+}
+
+// unmarshalValue stores an attribute value into rv.
+func (c *Codecs) unmarshalValue(val *dynamodb.AttributeValue, rv reflect.Value, path string) error {
+	if val.NULL != nil && *val.NULL {
+		rv.Set(reflect.Zero(rv.Type()))
+		return nil
+	}
+	if codec, ok := c.lookup(rv.Type()); ok {
+		v, err := codec.Decode(val)
+		if err != nil {
+			return &ValueError{path, err}
+		}
+		if v == nil {
+			rv.Set(reflect.Zero(rv.Type()))
+			return nil
+		}
+		dv := reflect.ValueOf(v)
+		if !dv.Type().AssignableTo(rv.Type()) {
+			return &ValueError{path, fmt.Errorf("codec returned %s, want %s", dv.Type(), rv.Type())}
+		}
+		rv.Set(dv)
+		return nil
+	}
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		return c.unmarshalValue(val, rv.Elem(), path)
+	case reflect.Interface:
+		if rv.NumMethod() != 0 {
+			return &ValueError{path, fmt.Errorf("cannot unmarshal into type %s", rv.Type())}
+		}
+		v, err := c.unmarshalInterface(val, path)
+		if err != nil {
+			return err
+		}
+		if v != nil {
+			rv.Set(reflect.ValueOf(v))
+		}
+		return nil
+	case reflect.String:
+		if val.S == nil {
+			return &ValueError{path, ErrType}
+		}
+		rv.SetString(*val.S)
+	case reflect.Bool:
+		if val.BOOL == nil {
+			return &ValueError{path, ErrType}
+		}
+		rv.SetBool(*val.BOOL)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		if val.N == nil {
+			return &ValueError{path, ErrType}
+		}
+		return setNumber(rv, *val.N, path)
+	case reflect.Struct:
+		if val.M == nil {
+			return &ValueError{path, ErrType}
+		}
+		for _, f := range structFields(rv.Type()) {
+			fval, ok := val.M[f.name]
+			if !ok || fval == nil {
+				continue
+			}
+			if err := c.unmarshalValue(fval, rv.FieldByIndex(f.index), joinPath(path, f.name)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if val.M == nil {
+			return &ValueError{path, ErrType}
+		}
+		t := rv.Type()
+		if t.Key().Kind() != reflect.String {
+			return &ValueError{path, fmt.Errorf("cannot unmarshal map key type %s", t.Key())}
+		}
+		if rv.IsNil() {
+			rv.Set(reflect.MakeMap(t))
+		}
+		for k, mval := range val.M {
+			if mval == nil {
+				continue
+			}
+			elem := reflect.New(t.Elem()).Elem()
+			if err := c.unmarshalValue(mval, elem, joinPath(path, k)); err != nil {
+				return err
+			}
+			rv.SetMapIndex(reflect.ValueOf(k).Convert(t.Key()), elem)
+		}
+	case reflect.Slice, reflect.Array:
+		return c.unmarshalList(val, rv, path)
+	default:
+		return &ValueError{path, fmt.Errorf("cannot unmarshal into type %s", rv.Type())}
+	}
+	return nil
+}
+
+// unmarshalList stores a list, set or binary value into a slice or array.
+func (c *Codecs) unmarshalList(val *dynamodb.AttributeValue, rv reflect.Value, path string) error {
+	var elems []*dynamodb.AttributeValue
+	switch {
+	case val.B != nil && rv.Type().Elem().Kind() == reflect.Uint8:
+		if rv.Kind() == reflect.Array {
+			if len(val.B) > rv.Len() {
+				return &ValueError{path, fmt.Errorf("%d bytes overflow %s", len(val.B), rv.Type())}
+			}
+			reflect.Copy(rv, reflect.ValueOf(val.B))
+			return nil
+		}
+		rv.SetBytes(append([]byte(nil), val.B...))
+		return nil
+	case val.L != nil:
+		elems = val.L
+	case val.SS != nil:
+		for _, s := range val.SS {
+			elems = append(elems, &dynamodb.AttributeValue{S: s})
+		}
+	case val.NS != nil:
+		for _, n := range val.NS {
+			elems = append(elems, &dynamodb.AttributeValue{N: n})
+		}
+	case val.BS != nil:
+		for _, b := range val.BS {
+			elems = append(elems, &dynamodb.AttributeValue{B: b})
+		}
+	default:
+		return &ValueError{path, ErrType}
+	}
+	if rv.Kind() == reflect.Array {
+		if len(elems) > rv.Len() {
+			return &ValueError{path, fmt.Errorf("%d elements overflow %s", len(elems), rv.Type())}
+		}
+	} else {
+		rv.Set(reflect.MakeSlice(rv.Type(), len(elems), len(elems)))
+	}
+	for i, elem := range elems {
+		if elem == nil {
+			continue
+		}
+		if err := c.unmarshalValue(elem, rv.Index(i), indexPath(path, i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unmarshalInterface converts an attribute value into a plain Go value.
+func (c *Codecs) unmarshalInterface(val *dynamodb.AttributeValue, path string) (interface{}, error) {
+	switch {
+	case val.S != nil:
+		return *val.S, nil
+	case val.N != nil:
+		f, err := strconv.ParseFloat(*val.N, 64)
+		if err != nil {
+			return nil, &ValueError{path, err}
+		}
+		return f, nil
+	case val.BOOL != nil:
+		return *val.BOOL, nil
+	case val.B != nil:
+		return val.B, nil
+	case val.SS != nil:
+		return aws.StringValueSlice(val.SS), nil
+	case val.NS != nil:
+		var ns []float64
+		err := c.unmarshalList(val, reflect.ValueOf(&ns).Elem(), path)
+		return ns, err
+	case val.BS != nil:
+		return val.BS, nil
+	case val.L != nil:
+		var l []interface{}
+		err := c.unmarshalList(val, reflect.ValueOf(&l).Elem(), path)
+		return l, err
+	case val.M != nil:
+		var m map[string]interface{}
+		err := c.unmarshalValue(val, reflect.ValueOf(&m).Elem(), path)
+		return m, err
+	}
+	return nil, nil
+}
+
+// setNumber parses a DynamoDB number into any numeric kind.
+func setNumber(rv reflect.Value, n string, path string) error {
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(n, 10, rv.Type().Bits())
+		if err != nil {
+			return &ValueError{path, err}
+		}
+		rv.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		i, err := strconv.ParseUint(n, 10, rv.Type().Bits())
+		if err != nil {
+			return &ValueError{path, err}
+		}
+		rv.SetUint(i)
+	default:
+		f, err := strconv.ParseFloat(n, rv.Type().Bits())
+		if err != nil {
+			return &ValueError{path, err}
+		}
+		rv.SetFloat(f)
+	}
+	return nil
+}
+
+// isZeroScalar reports whether rv is a zero number or false bool, which
+// omitempty leaves out.
+func isZeroScalar(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.Bool:
+		return !rv.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return rv.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return rv.Float() == 0
+	}
+	return false
+}
+
+func isNumberKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// bytesOf returns the contents of a byte slice or array.
+func bytesOf(rv reflect.Value) []byte {
+	if rv.Kind() == reflect.Slice {
+		return rv.Bytes()
+	}
+	b := make([]byte, rv.Len())
+	reflect.Copy(reflect.ValueOf(b), rv)
+	return b
+}
@@ -0,0 +1,341 @@
+package dynamis
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+type marshalAddress struct {
+	City   string `dynamis:"city"`
+	Street string `dynamis:"street"`
+}
+
+type marshalBase struct {
+	ID string `dynamis:"id"`
+}
+
+type marshalUser struct {
+	marshalBase
+	Name     string            `dynamis:"name"`
+	Age      int               `dynamis:"age,omitempty"`
+	Score    float64           `dynamis:"score"`
+	Admin    bool              `dynamis:"admin,omitempty"`
+	Size     uint8             `dynamis:"size"`
+	Avatar   []byte            `dynamis:"avatar"`
+	Tags     []string          `dynamis:"tags,set"`
+	Lucky    []int             `dynamis:"lucky,set"`
+	Keys     [][]byte          `dynamis:"keys,set"`
+	Events   []string          `dynamis:"events"`
+	Address  marshalAddress    `dynamis:"address"`
+	Previous *marshalAddress   `dynamis:"previous"`
+	Labels   map[string]string `dynamis:"labels"`
+	Secret   string            `dynamis:"-"`
+	Untagged string
+	private  string
+}
+
+func TestMarshal(t *testing.T) {
+	tests := []struct {
+		v    interface{}
+		want map[string]*dynamodb.AttributeValue
+	}{
+		{
+			// Zero values follow the Set helper rules.
+			v: marshalUser{},
+			want: map[string]*dynamodb.AttributeValue{
+				"score": {N: aws.String("0")},
+				"size":  {N: aws.String("0")},
+			},
+		},
+		{
+			// Every kind of value.
+			v: &marshalUser{
+				marshalBase: marshalBase{ID: "u1"},
+				Name:        "Ryan",
+				Age:         33,
+				Score:       1.5,
+				Admin:       true,
+				Size:        2,
+				Avatar:      []byte("png"),
+				Tags:        []string{"b", "a", "b"},
+				Lucky:       []int{7, 3},
+				Keys:        [][]byte{[]byte("k")},
+				Events:      []string{"signup", "", "login"},
+				Address:     marshalAddress{City: "Portland"},
+				Previous:    &marshalAddress{},
+				Labels:      map[string]string{"team": "core", "empty": ""},
+				Secret:      "shh",
+				Untagged:    "u",
+				private:     "p",
+			},
+			want: map[string]*dynamodb.AttributeValue{
+				"id":     {S: aws.String("u1")},
+				"name":   {S: aws.String("Ryan")},
+				"age":    {N: aws.String("33")},
+				"score":  {N: aws.String("1.5")},
+				"admin":  {BOOL: aws.Bool(true)},
+				"size":   {N: aws.String("2")},
+				"avatar": {B: []byte("png")},
+				"tags":   {SS: aws.StringSlice([]string{"a", "b"})},
+				"lucky":  {NS: aws.StringSlice([]string{"3", "7"})},
+				"keys":   {BS: [][]byte{[]byte("k")}},
+				"events": {L: []*dynamodb.AttributeValue{
+					{S: aws.String("signup")},
+					{S: aws.String("login")},
+				}},
+				"address": {M: map[string]*dynamodb.AttributeValue{
+					"city": {S: aws.String("Portland")},
+				}},
+				"labels": {M: map[string]*dynamodb.AttributeValue{
+					"team": {S: aws.String("core")},
+				}},
+				"Untagged": {S: aws.String("u")},
+			},
+		},
+		{
+			// Maps with string keys.
+			v: map[string]interface{}{"s": "v", "n": 1, "nil": nil},
+			want: map[string]*dynamodb.AttributeValue{
+				"s": {S: aws.String("v")},
+				"n": {N: aws.String("1")},
+			},
+		},
+	}
+	for i, test := range tests {
+		got, err := Marshal(test.v)
+		if err != nil {
+			t.Errorf("%d Marshal() error %s", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%d Marshal() got %#v, want %#v", i, got, test.want)
+		}
+	}
+}
+
+// MarshalExtra is exported so it can be embedded by pointer.
+type MarshalExtra struct {
+	Note string `dynamis:"note"`
+}
+
+func TestEncodeFloatRange(t *testing.T) {
+	item := make(map[string]*dynamodb.AttributeValue)
+	w := NewValueWriter(item)
+	if err := w.Encode("big", 1e200); err == nil {
+		t.Errorf("Encode(1e200) want error")
+	}
+	if err := w.Encode("small", 1e-200); err != nil {
+		t.Errorf("Encode(1e-200) error %s", err)
+	}
+	if got, want := item, (map[string]*dynamodb.AttributeValue{"small": {N: aws.String("0")}}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Encode() got %#v, want %#v", got, want)
+	}
+	if _, _, _, err := Cond.Eq("f", math.Inf(1)).Build(); err == nil {
+		t.Errorf("Cond.Eq(Inf) want error")
+	}
+}
+
+func TestMarshalEmbeddedPointer(t *testing.T) {
+	type withExtra struct {
+		*MarshalExtra
+		Name string `dynamis:"name"`
+	}
+	item, err := Marshal(withExtra{&MarshalExtra{"hi"}, "Ryan"})
+	if err != nil {
+		t.Fatalf("Marshal() error %s", err)
+	}
+	// Embedded pointers are stored as a map, not flattened.
+	want := map[string]*dynamodb.AttributeValue{
+		"MarshalExtra": {M: map[string]*dynamodb.AttributeValue{
+			"note": {S: aws.String("hi")},
+		}},
+		"name": {S: aws.String("Ryan")},
+	}
+	if !reflect.DeepEqual(item, want) {
+		t.Errorf("Marshal() got %#v, want %#v", item, want)
+	}
+	var got withExtra
+	if err := Unmarshal(item, &got); err != nil {
+		t.Fatalf("Unmarshal() error %s", err)
+	}
+	if got.MarshalExtra == nil || got.Note != "hi" || got.Name != "Ryan" {
+		t.Errorf("Unmarshal() got %#v", got)
+	}
+}
+
+func TestMarshalErrors(t *testing.T) {
+	tests := []struct {
+		v    interface{}
+		path string
+	}{
+		{v: "string"},
+		{v: nil},
+		{v: map[int]string{1: "a"}},
+		{
+			v: struct {
+				C chan int `dynamis:"c"`
+			}{make(chan int)},
+			path: "c",
+		},
+		{
+			v: struct {
+				S []bool `dynamis:"s,set"`
+			}{[]bool{true}},
+			path: "s",
+		},
+		{
+			v: struct {
+				M map[string][]func() `dynamis:"m"`
+			}{map[string][]func(){"f": {func() {}}}},
+			path: "m.f[0]",
+		},
+		{
+			v: struct {
+				F float64 `dynamis:"f"`
+			}{1e200},
+			path: "f",
+		},
+		{
+			v: struct {
+				F []float64 `dynamis:"f"`
+			}{[]float64{1, math.NaN()}},
+			path: "f[1]",
+		},
+	}
+	for i, test := range tests {
+		_, err := Marshal(test.v)
+		if err == nil {
+			t.Errorf("%d Marshal() want error", i)
+			continue
+		}
+		if test.path != "" {
+			if verr, ok := err.(*ValueError); !ok || verr.Path != test.path {
+				t.Errorf("%d Marshal() got %#v, want path %q", i, err, test.path)
+			}
+		}
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	in := marshalUser{
+		marshalBase: marshalBase{ID: "u1"},
+		Name:        "Ryan",
+		Age:         33,
+		Score:       1.5,
+		Admin:       true,
+		Size:        2,
+		Avatar:      []byte("png"),
+		Tags:        []string{"a", "b"},
+		Lucky:       []int{3, 7},
+		Keys:        [][]byte{[]byte("k")},
+		Events:      []string{"signup", "login"},
+		Address:     marshalAddress{City: "Portland"},
+		Previous:    &marshalAddress{City: "Seattle"},
+		Labels:      map[string]string{"team": "core"},
+		Untagged:    "u",
+	}
+	item, err := Marshal(in)
+	if err != nil {
+		t.Fatalf("Marshal() error %s", err)
+	}
+	var out marshalUser
+	if err := Unmarshal(item, &out); err != nil {
+		t.Fatalf("Unmarshal() error %s", err)
+	}
+	if !reflect.DeepEqual(out, in) {
+		t.Errorf("Unmarshal() got %#v, want %#v", out, in)
+	}
+
+	// Missing attributes leave fields alone, NULL attributes clear them.
+	out = marshalUser{Name: "keep", Untagged: "clear"}
+	err = Unmarshal(map[string]*dynamodb.AttributeValue{
+		"Untagged": {NULL: aws.Bool(true)},
+	}, &out)
+	if err != nil || out.Name != "keep" || out.Untagged != "" {
+		t.Errorf("Unmarshal() got (%#v, %v)", out, err)
+	}
+
+	// Unmarshal into a generic map.
+	var m map[string]interface{}
+	err = Unmarshal(map[string]*dynamodb.AttributeValue{
+		"s":  {S: aws.String("v")},
+		"n":  {N: aws.String("1.5")},
+		"ns": {NS: aws.StringSlice([]string{"1"})},
+		"l":  {L: []*dynamodb.AttributeValue{{BOOL: aws.Bool(true)}}},
+		"m":  {M: map[string]*dynamodb.AttributeValue{"k": {S: aws.String("v")}}},
+	}, &m)
+	want := map[string]interface{}{
+		"s":  "v",
+		"n":  1.5,
+		"ns": []float64{1},
+		"l":  []interface{}{true},
+		"m":  map[string]interface{}{"k": "v"},
+	}
+	if err != nil || !reflect.DeepEqual(m, want) {
+		t.Errorf("Unmarshal() got (%#v, %v), want %#v", m, err, want)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		item map[string]*dynamodb.AttributeValue
+		v    interface{}
+		path string
+		err  error
+	}{
+		{
+			// Not a pointer.
+			v: marshalUser{},
+		},
+		{
+			// Not a struct or map.
+			v: new(string),
+		},
+		{
+			item: map[string]*dynamodb.AttributeValue{"name": {N: aws.String("1")}},
+			v:    &marshalUser{},
+			path: "name",
+			err:  ErrType,
+		},
+		{
+			item: map[string]*dynamodb.AttributeValue{"size": {N: aws.String("256")}},
+			v:    &marshalUser{},
+			path: "size",
+		},
+		{
+			item: map[string]*dynamodb.AttributeValue{"address": {M: map[string]*dynamodb.AttributeValue{
+				"city": {BOOL: aws.Bool(true)},
+			}}},
+			v:    &marshalUser{},
+			path: "address.city",
+			err:  ErrType,
+		},
+		{
+			item: map[string]*dynamodb.AttributeValue{"lucky": {NS: aws.StringSlice([]string{"1", "x"})}},
+			v:    &marshalUser{},
+			path: "lucky[1]",
+		},
+	}
+	for i, test := range tests {
+		err := Unmarshal(test.item, test.v)
+		if err == nil {
+			t.Errorf("%d Unmarshal() want error", i)
+			continue
+		}
+		if test.path == "" {
+			continue
+		}
+		verr, ok := err.(*ValueError)
+		if !ok || verr.Path != test.path {
+			t.Errorf("%d Unmarshal() got %#v, want path %q", i, err, test.path)
+			continue
+		}
+		if test.err != nil && verr.Err != test.err {
+			t.Errorf("%d Unmarshal() got %v, want %v", i, verr.Err, test.err)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package dynamis
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// Decimal is a number in decimal string form, such as "12.50" or "1E+40". It
+// is stored exactly as written, so it keeps precision that a float64 would
+// lose.
+type Decimal string
+
+// Limits of the DynamoDB number type.
+const (
+	// MaxNumberDigits is the number of significant digits DynamoDB keeps.
+	MaxNumberDigits = 38
+
+	// MaxNumberExponent is the largest power of ten of a number's leading
+	// digit, as in 9.9999999999999999999999999999999999999E+125.
+	MaxNumberExponent = 125
+
+	// MinNumberExponent is the smallest power of ten of a non-zero number's
+	// leading digit, as in 1E-130.
+	MinNumberExponent = -130
+)
+
+// bigFloatPrec is the precision of floats read by BigFloat. It holds the 38
+// decimal digits DynamoDB keeps.
+const bigFloatPrec = 128
+
+// ValidateNumber returns an error if a number in decimal string form can't be
+// stored by DynamoDB, because it is malformed, has more than MaxNumberDigits
+// significant digits, or is outside the range DynamoDB supports.
+func ValidateNumber(n string) error {
+	s := strings.TrimLeft(n, "+-")
+	if len(n)-len(s) > 1 {
+		return fmt.Errorf("dynamis: invalid number %q", n)
+	}
+	exp := 0
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		var err error
+		if exp, err = strconv.Atoi(s[i+1:]); err != nil {
+			if ne, ok := err.(*strconv.NumError); ok && ne.Err == strconv.ErrRange {
+				return fmt.Errorf("dynamis: number %q is out of range", n)
+			}
+			return fmt.Errorf("dynamis: invalid number %q", n)
+		}
+		s = s[:i]
+	}
+	digits := s
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		digits = s[:i] + s[i+1:]
+		exp -= len(s) - i - 1
+	}
+	if digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return fmt.Errorf("dynamis: invalid number %q", n)
+	}
+	digits = strings.TrimLeft(digits, "0")
+	if digits == "" {
+		return nil
+	}
+	trimmed := strings.TrimRight(digits, "0")
+	exp += len(digits) - len(trimmed)
+	if len(trimmed) > MaxNumberDigits {
+		return fmt.Errorf("dynamis: number %q has more than %d significant digits", n, MaxNumberDigits)
+	}
+	switch lead := exp + len(trimmed) - 1; {
+	case lead > MaxNumberExponent:
+		return fmt.Errorf("dynamis: number %q is too large", n)
+	case lead < MinNumberExponent:
+		return fmt.Errorf("dynamis: number %q is too small", n)
+	}
+	return nil
+}
+
+// BigInt returns a big.Int from a DynamoDB attribute value. If anything goes
+// wrong reading or parsing the value, including a number with a fraction, 0
+// is returned.
+func BigInt(item map[string]*dynamodb.AttributeValue, key string) *big.Int {
+	i, err := bigIntE(item, key)
+	if err != nil {
+		return new(big.Int)
+	}
+	return i
+}
+
+// BigFloat returns a big.Float with 128 bits of precision from a DynamoDB
+// attribute value. If anything goes wrong reading or parsing the value, 0 is
+// returned.
+func BigFloat(item map[string]*dynamodb.AttributeValue, key string) *big.Float {
+	f, err := bigFloatE(item, key)
+	if err != nil {
+		return new(big.Float).SetPrec(bigFloatPrec)
+	}
+	return f
+}
+
+// Num returns a number from a DynamoDB attribute value exactly as stored. If
+// anything goes wrong reading the value, an empty Decimal is returned.
+func Num(item map[string]*dynamodb.AttributeValue, key string) Decimal {
+	n, _ := numE(item, key)
+	return Decimal(n)
+}
+
+// SetBigInt stores a big.Int attribute. If the value is nil, it is not
+// stored. It returns an error, and stores nothing, if the value can't be
+// represented by DynamoDB.
+func SetBigInt(item map[string]*dynamodb.AttributeValue, key string, val *big.Int) error {
+	if val == nil {
+		return nil
+	}
+	return storeNum(item, key, val.String())
+}
+
+// SetBigFloat stores a big.Float attribute using the shortest decimal form
+// that represents it exactly. If the value is nil, it is not stored. It
+// returns an error, and stores nothing, if the value can't be represented by
+// DynamoDB.
+func SetBigFloat(item map[string]*dynamodb.AttributeValue, key string, val *big.Float) error {
+	if val == nil {
+		return nil
+	}
+	if val.IsInf() {
+		return fmt.Errorf("dynamis: number %s is out of range", val.String())
+	}
+	return storeNum(item, key, val.Text('g', -1))
+}
+
+// SetNum stores a Decimal attribute exactly as written. If the value is
+// empty, it is not stored. It returns an error, and stores nothing, if the
+// value can't be represented by DynamoDB.
+func SetNum(item map[string]*dynamodb.AttributeValue, key string, val Decimal) error {
+	if val == "" {
+		return nil
+	}
+	return storeNum(item, key, string(val))
+}
+
+// storeNum validates and stores a number attribute.
+func storeNum(item map[string]*dynamodb.AttributeValue, key string, n string) error {
+	if err := ValidateNumber(n); err != nil {
+		return err
+	}
+	if key != "" {
+		item[key] = &dynamodb.AttributeValue{
+			N: aws.String(n),
+		}
+	}
+	return nil
+}
+
+func bigIntE(item map[string]*dynamodb.AttributeValue, key string) (*big.Int, error) {
+	n, err := numE(item, key)
+	if err != nil {
+		return nil, err
+	}
+	r, ok := new(big.Rat).SetString(n)
+	if !ok {
+		return nil, fmt.Errorf("invalid number %q", n)
+	}
+	if !r.IsInt() {
+		return nil, fmt.Errorf("number %q is not an integer", n)
+	}
+	return r.Num(), nil
+}
+
+func bigFloatE(item map[string]*dynamodb.AttributeValue, key string) (*big.Float, error) {
+	n, err := numE(item, key)
+	if err != nil {
+		return nil, err
+	}
+	f, _, err := big.ParseFloat(n, 10, bigFloatPrec, big.ToNearestEven)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
@@ -0,0 +1,252 @@
+package dynamis
+
+import (
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestValidateNumber(t *testing.T) {
+	tests := []struct {
+		n  string
+		ok bool
+	}{
+		{"0", true},
+		{"-0.000", true},
+		{"+12", true},
+		{"12.50", true},
+		{".5", true},
+		{"5.", true},
+		{"1E+3", true},
+		{"1e-3", true},
+		{"12345678901234567890123456789012345678", true},
+		{"123456789012345678901234567890123456780000", true},
+		{"0.00012345678901234567890123456789012345678", true},
+		{"9.9999999999999999999999999999999999999E+125", true},
+		{"-9.9999999999999999999999999999999999999E+125", true},
+		{"1E-130", true},
+		{"100E-132", true},
+		{"123456789012345678901234567890123456789", false},
+		{"1.23456789012345678901234567890123456789", false},
+		{"1E+126", false},
+		{"10E+125", false},
+		{"1E-131", false},
+		{"0.1E-130", false},
+		{"1E+99999999999999999999", false},
+		{"", false},
+		{"-", false},
+		{".", false},
+		{"--1", false},
+		{"1.2.3", false},
+		{"abc", false},
+		{"1E", false},
+		{"NaN", false},
+		{"Inf", false},
+		{"0x10", false},
+	}
+	for i, test := range tests {
+		err := ValidateNumber(test.n)
+		if got := err == nil; got != test.ok {
+			t.Errorf("%d ValidateNumber(%q) got %v, want ok=%v", i, test.n, err, test.ok)
+		}
+	}
+}
+
+func TestBigInt(t *testing.T) {
+	huge, _ := new(big.Int).SetString("12345678901234567890123456789012345678", 10)
+	tests := []struct {
+		item map[string]*dynamodb.AttributeValue
+		want *big.Int
+	}{
+		{
+			// Item is nil, returns the zero value.
+			item: nil,
+			want: big.NewInt(0),
+		},
+		{
+			// Value has a fraction, returns the zero value.
+			item: map[string]*dynamodb.AttributeValue{"k": {N: aws.String("1.5")}},
+			want: big.NewInt(0),
+		},
+		{
+			// Value has an exponent.
+			item: map[string]*dynamodb.AttributeValue{"k": {N: aws.String("1E+3")}},
+			want: big.NewInt(1000),
+		},
+		{
+			// Value is larger than int64.
+			item: map[string]*dynamodb.AttributeValue{"k": {N: aws.String("12345678901234567890123456789012345678")}},
+			want: huge,
+		},
+	}
+	for i, test := range tests {
+		got := BigInt(test.item, "k")
+		if got.Cmp(test.want) != 0 {
+			t.Errorf("%d BigInt() got %s, want %s", i, got, test.want)
+		}
+	}
+}
+
+func TestBigFloat(t *testing.T) {
+	tests := []struct {
+		item map[string]*dynamodb.AttributeValue
+		want string
+	}{
+		{
+			// Item is nil, returns the zero value.
+			item: nil,
+			want: "0",
+		},
+		{
+			// Value is not a number, returns the zero value.
+			item: map[string]*dynamodb.AttributeValue{"k": {S: aws.String("1")}},
+			want: "0",
+		},
+		{
+			// Value keeps 38 digits of precision.
+			item: map[string]*dynamodb.AttributeValue{"k": {N: aws.String("1.2345678901234567890123456789012345678")}},
+			want: "1.2345678901234567890123456789012345678",
+		},
+		{
+			// Value at the top of the range.
+			item: map[string]*dynamodb.AttributeValue{"k": {N: aws.String("9.9E+125")}},
+			want: "9.9e+125",
+		},
+	}
+	for i, test := range tests {
+		got := BigFloat(test.item, "k")
+		if s := got.Text('g', 38); s != test.want {
+			t.Errorf("%d BigFloat() got %s, want %s", i, s, test.want)
+		}
+	}
+}
+
+func TestNum(t *testing.T) {
+	item := map[string]*dynamodb.AttributeValue{"k": {N: aws.String("12.50")}}
+	if got, want := Num(item, "k"), Decimal("12.50"); got != want {
+		t.Errorf("Num() got %#v, want %#v", got, want)
+	}
+	if got := Num(item, "missing"); got != "" {
+		t.Errorf("Num(missing) got %#v", got)
+	}
+}
+
+func TestSetBigNumbers(t *testing.T) {
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890123456789", 10)
+	tests := []struct {
+		set  func(map[string]*dynamodb.AttributeValue) error
+		want map[string]*dynamodb.AttributeValue
+		err  bool
+	}{
+		{
+			set: func(item map[string]*dynamodb.AttributeValue) error {
+				return SetBigInt(item, "k", nil)
+			},
+			want: map[string]*dynamodb.AttributeValue{},
+		},
+		{
+			set: func(item map[string]*dynamodb.AttributeValue) error {
+				return SetBigInt(item, "k", big.NewInt(-33))
+			},
+			want: map[string]*dynamodb.AttributeValue{"k": {N: aws.String("-33")}},
+		},
+		{
+			set: func(item map[string]*dynamodb.AttributeValue) error {
+				return SetBigInt(item, "k", huge)
+			},
+			want: map[string]*dynamodb.AttributeValue{},
+			err:  true,
+		},
+		{
+			set: func(item map[string]*dynamodb.AttributeValue) error {
+				return SetBigFloat(item, "k", big.NewFloat(1.25))
+			},
+			want: map[string]*dynamodb.AttributeValue{"k": {N: aws.String("1.25")}},
+		},
+		{
+			set: func(item map[string]*dynamodb.AttributeValue) error {
+				return SetBigFloat(item, "k", new(big.Float).SetInf(false))
+			},
+			want: map[string]*dynamodb.AttributeValue{},
+			err:  true,
+		},
+		{
+			set: func(item map[string]*dynamodb.AttributeValue) error {
+				return SetBigFloat(item, "k", big.NewFloat(1e200))
+			},
+			want: map[string]*dynamodb.AttributeValue{},
+			err:  true,
+		},
+		{
+			set: func(item map[string]*dynamodb.AttributeValue) error {
+				return SetNum(item, "k", "")
+			},
+			want: map[string]*dynamodb.AttributeValue{},
+		},
+		{
+			set: func(item map[string]*dynamodb.AttributeValue) error {
+				return SetNum(item, "k", "12.50")
+			},
+			want: map[string]*dynamodb.AttributeValue{"k": {N: aws.String("12.50")}},
+		},
+		{
+			set: func(item map[string]*dynamodb.AttributeValue) error {
+				return SetNum(item, "k", "twelve")
+			},
+			want: map[string]*dynamodb.AttributeValue{},
+			err:  true,
+		},
+		{
+			set: func(item map[string]*dynamodb.AttributeValue) error {
+				SetFloat64(item, "k", math.MaxFloat64)
+				return nil
+			},
+			want: map[string]*dynamodb.AttributeValue{},
+		},
+	}
+	for i, test := range tests {
+		item := map[string]*dynamodb.AttributeValue{}
+		err := test.set(item)
+		if got := err != nil; got != test.err {
+			t.Errorf("%d got error %v, want error=%v", i, err, test.err)
+		}
+		if !reflect.DeepEqual(item, test.want) {
+			t.Errorf("%d got %#v, want %#v", i, item, test.want)
+		}
+	}
+}
+
+func TestMarshalBigNumbers(t *testing.T) {
+	type record struct {
+		I *big.Int   `dynamis:"i"`
+		F *big.Float `dynamis:"f"`
+		D Decimal    `dynamis:"d"`
+	}
+	in := record{big.NewInt(7), big.NewFloat(0.5), "12.50"}
+	item, err := Marshal(in)
+	if err != nil {
+		t.Fatalf("Marshal() error %s", err)
+	}
+	want := map[string]*dynamodb.AttributeValue{
+		"i": {N: aws.String("7")},
+		"f": {N: aws.String("0.5")},
+		"d": {N: aws.String("12.50")},
+	}
+	if !reflect.DeepEqual(item, want) {
+		t.Errorf("Marshal() got %#v, want %#v", item, want)
+	}
+	var out record
+	if err := Unmarshal(item, &out); err != nil {
+		t.Fatalf("Unmarshal() error %s", err)
+	}
+	if out.I.Cmp(in.I) != 0 || out.F.Cmp(in.F) != 0 || out.D != in.D {
+		t.Errorf("Unmarshal() got %#v, want %#v", out, in)
+	}
+	if _, err := Marshal(record{D: "nope"}); err == nil {
+		t.Errorf("Marshal(invalid) want error")
+	}
+}
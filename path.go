@@ -0,0 +1,121 @@
+package dynamis
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// pathElem is one step of a document path: either a map key or a list index.
+type pathElem struct {
+	name    string
+	index   int
+	isIndex bool
+}
+
+// parsePath splits a document path such as "address.city" or
+// "events[3].type" into its elements. Map keys are separated by dots and list
+// indexes are written in brackets after a key.
+func parsePath(path string) ([]pathElem, error) {
+	var elems []pathElem
+	for _, part := range strings.Split(path, ".") {
+		name := part
+		if i := strings.IndexByte(part, '['); i >= 0 {
+			name = part[:i]
+			part = part[i:]
+		} else {
+			part = ""
+		}
+		if name == "" {
+			return nil, fmt.Errorf("dynamis: invalid path %q", path)
+		}
+		elems = append(elems, pathElem{name: name})
+		for part != "" {
+			end := strings.IndexByte(part, ']')
+			if part[0] != '[' || end < 0 {
+				return nil, fmt.Errorf("dynamis: invalid path %q", path)
+			}
+			n, err := strconv.Atoi(part[1:end])
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("dynamis: invalid index in path %q", path)
+			}
+			elems = append(elems, pathElem{index: n, isIndex: true})
+			part = part[end+1:]
+		}
+	}
+	return elems, nil
+}
+
+// formatPath joins path elements back into a document path.
+func formatPath(elems []pathElem) string {
+	var path string
+	for _, e := range elems {
+		if e.isIndex {
+			path = indexPath(path, e.index)
+		} else {
+			path = joinPath(path, e.name)
+		}
+	}
+	return path
+}
+
+// joinPath appends a map key to a document path. ElemKey names the value
+// itself, so it leaves the path as it is.
+func joinPath(path, key string) string {
+	if path == "" || key == ElemKey {
+		return path + key
+	}
+	return path + "." + key
+}
+
+// indexPath appends a list index to a document path.
+func indexPath(path string, i int) string {
+	return path + "[" + strconv.Itoa(i) + "]"
+}
+
+// pathsOverlap reports whether two paths are the same, or one is nested
+// within the other.
+func pathsOverlap(a, b []pathElem) bool {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// lookupPath follows the path elements from the item and returns the
+// attribute value found there. If an element is missing or its parent has the
+// wrong type, it returns ErrMissing or ErrType along with the position of the
+// element that failed.
+func lookupPath(item map[string]*dynamodb.AttributeValue, elems []pathElem) (*dynamodb.AttributeValue, int, error) {
+	var val *dynamodb.AttributeValue
+	for i, e := range elems {
+		switch {
+		case i == 0:
+			val = item[e.name]
+		case e.isIndex:
+			if val.L == nil {
+				return nil, i, ErrType
+			}
+			if e.index >= len(val.L) {
+				return nil, i, ErrMissing
+			}
+			val = val.L[e.index]
+		default:
+			if val.M == nil {
+				return nil, i, ErrType
+			}
+			val = val.M[e.name]
+		}
+		if val == nil {
+			return nil, i, ErrMissing
+		}
+	}
+	return val, len(elems) - 1, nil
+}
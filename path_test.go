@@ -0,0 +1,104 @@
+package dynamis
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		path string
+		want []pathElem
+		err  bool
+	}{
+		{
+			path: "a",
+			want: []pathElem{{name: "a"}},
+		},
+		{
+			path: "a.b",
+			want: []pathElem{{name: "a"}, {name: "b"}},
+		},
+		{
+			path: "a[3].b",
+			want: []pathElem{{name: "a"}, {index: 3, isIndex: true}, {name: "b"}},
+		},
+		{
+			path: "a[0][1]",
+			want: []pathElem{{name: "a"}, {index: 0, isIndex: true}, {index: 1, isIndex: true}},
+		},
+		{path: "", err: true},
+		{path: "a..b", err: true},
+		{path: "[0]", err: true},
+		{path: "a[x]", err: true},
+		{path: "a[-1]", err: true},
+		{path: "a[0", err: true},
+		{path: "a[0]b", err: true},
+	}
+	for i, test := range tests {
+		got, err := parsePath(test.path)
+		if test.err {
+			if err == nil {
+				t.Errorf("%d parsePath(%q) want error", i, test.path)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%d parsePath(%q) error %s", i, test.path, err)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%d parsePath(%q) got %#v, want %#v", i, test.path, got, test.want)
+		}
+	}
+}
+
+func TestLookupPath(t *testing.T) {
+	city := &dynamodb.AttributeValue{S: aws.String("Portland")}
+	item := map[string]*dynamodb.AttributeValue{
+		"address": {M: map[string]*dynamodb.AttributeValue{"city": city}},
+		"events": {L: []*dynamodb.AttributeValue{
+			{M: map[string]*dynamodb.AttributeValue{"city": city}},
+		}},
+	}
+	tests := []struct {
+		path string
+		want *dynamodb.AttributeValue
+		pos  int
+		err  error
+	}{
+		{"address.city", city, 1, nil},
+		{"events[0].city", city, 2, nil},
+		{"events[1].city", nil, 1, ErrMissing},
+		{"address[0]", nil, 1, ErrType},
+		{"events.city", nil, 1, ErrType},
+		{"nope.city", nil, 0, ErrMissing},
+	}
+	for i, test := range tests {
+		elems, err := parsePath(test.path)
+		if err != nil {
+			t.Fatalf("%d parsePath(%q) error %s", i, test.path, err)
+		}
+		got, pos, err := lookupPath(item, elems)
+		if got != test.want {
+			t.Errorf("%d lookupPath(%q) got %#v, want %#v", i, test.path, got, test.want)
+		}
+		if pos != test.pos || err != test.err {
+			t.Errorf("%d lookupPath(%q) got (%d, %v), want (%d, %v)", i, test.path, pos, err, test.pos, test.err)
+		}
+	}
+}
+
+func TestFormatPath(t *testing.T) {
+	for _, path := range []string{"a", "a.b", "a[3].b", "a[0][1]"} {
+		elems, err := parsePath(path)
+		if err != nil {
+			t.Fatalf("parsePath(%q) error %s", path, err)
+		}
+		if got := formatPath(elems); got != path {
+			t.Errorf("formatPath(%q) got %q", path, got)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package dynamis
+
+import "strings"
+
+// Projection chooses the attributes read from each item, as the
+// ProjectionExpression of a GetItem, Query or Scan. Paths may be nested, such
+// as "address.city" or "events[0]", and every name is given a placeholder, so
+// reserved words such as "name" and "status" need no special care.
+type Projection struct {
+	paths []string
+}
+
+// Project returns a projection of the attributes at the paths. With no paths,
+// every attribute is read.
+func Project(paths ...string) Projection {
+	return Projection{paths}
+}
+
+// expr builds the projection with a shared builder. It returns "" if every
+// attribute is read.
+func (p Projection) expr(b *exprBuilder) (string, error) {
+	names := make([]string, len(p.paths))
+	for i, path := range p.paths {
+		name, err := b.name(path)
+		if err != nil {
+			return "", err
+		}
+		names[i] = name
+	}
+	return strings.Join(names, ", "), nil
+}
+
+// apply builds the projection with a shared builder, returning nil if every
+// attribute is read. A nil projection reads every attribute.
+func (p *Projection) apply(b *exprBuilder) (*string, error) {
+	if p == nil {
+		return nil, nil
+	}
+	expr, err := p.expr(b)
+	if err != nil || expr == "" {
+		return nil, err
+	}
+	return &expr, nil
+}
+
+// Build returns the projection expression along with the map to use as its
+// ExpressionAttributeNames. If every attribute is read, the expression is ""
+// and the map is nil.
+func (p Projection) Build() (string, map[string]*string, error) {
+	b := newExprBuilder(DefaultCodecs)
+	expr, err := p.expr(b)
+	if err != nil {
+		return "", nil, err
+	}
+	return expr, b.attrNames(), nil
+}
+
+// Project returns a wrapper over the same table that reads only the projected
+// attributes in Get, Rows, EachRow and Query. Row counts are not affected.
+func (t Table) Project(p Projection) Table {
+	t.projection = &p
+	return t
+}
+
+// Project returns a wrapper over the same index that reads only the projected
+// attributes in Rows, EachRow and Query. Row counts are not affected.
+func (i Index) Project(p Projection) Index {
+	i.table.projection = &p
+	return i
+}
@@ -0,0 +1,141 @@
+package dynamis
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+func TestProjection(t *testing.T) {
+	tests := []struct {
+		p     Projection
+		expr  string
+		names map[string]*string
+		err   bool
+	}{
+		{
+			p: Project(),
+		},
+		{
+			p:     Project("name", "status"),
+			expr:  "#n0, #n1",
+			names: map[string]*string{"#n0": aws.String("name"), "#n1": aws.String("status")},
+		},
+		{
+			p:     Project("address.city", "events[0]", "address.zip"),
+			expr:  "#n0.#n1, #n2[0], #n0.#n3",
+			names: map[string]*string{"#n0": aws.String("address"), "#n1": aws.String("city"), "#n2": aws.String("events"), "#n3": aws.String("zip")},
+		},
+		{
+			p:   Project("ok", "bad[x]"),
+			err: true,
+		},
+	}
+	for i, test := range tests {
+		expr, names, err := test.p.Build()
+		if (err != nil) != test.err {
+			t.Errorf("%d Build() error %v", i, err)
+			continue
+		}
+		if expr != test.expr {
+			t.Errorf("%d Build() got %q, want %q", i, expr, test.expr)
+		}
+		if !reflect.DeepEqual(names, test.names) {
+			t.Errorf("%d Build() names got %#v, want %#v", i, names, test.names)
+		}
+	}
+}
+
+func TestProjectionInputs(t *testing.T) {
+	table := Table{tableName: "users"}.Project(Project("name"))
+
+	scan, err := table.scanInput()
+	if err != nil || aws.StringValue(scan.ProjectionExpression) != "#n0" || len(scan.ExpressionAttributeNames) != 1 {
+		t.Errorf("scanInput() got (%#v, %v)", scan, err)
+	}
+
+	// Counts don't project.
+	count, err := table.countInput()
+	if err != nil || count.ProjectionExpression != nil || count.ExpressionAttributeNames != nil {
+		t.Errorf("countInput() got (%#v, %v)", count, err)
+	}
+
+	// Queries share placeholders with the key condition.
+	query, err := table.queryInput(Key("name").Eq("Ryan"), nil)
+	if err != nil {
+		t.Fatalf("queryInput() error %s", err)
+	}
+	if got, want := aws.StringValue(query.ProjectionExpression), "#n0"; got != want {
+		t.Errorf("queryInput() projection got %q, want %q", got, want)
+	}
+
+	if _, err := table.Project(Project("bad[x]")).scanInput(); err == nil {
+		t.Errorf("scanInput(bad) want error")
+	}
+
+	// Projected tables are still comparable, so they can be map keys.
+	plain := Table{tableName: "users"}
+	copied := table
+	if table == plain || copied != table {
+		t.Errorf("Table comparison got (%v, %v), want (false, true)", table == plain, copied == table)
+	}
+	if seen := map[Table]bool{table: true}; !seen[copied] || seen[plain] {
+		t.Errorf("Table map key got %#v", seen)
+	}
+}
+
+func TestTableProject(t *testing.T) {
+	tbl := newTable()
+	if err := createStrTable(tbl); err != nil {
+		t.Fatalf("Failed initializing: %s", err)
+	}
+	table := CheckTable(tbl.db, tbl.name)
+	err := table.Put(func(w ValueWriter) {
+		w.Str("str", "k1")
+		w.Str("name", "Ryan")
+		w.Str("status", "active")
+		w.Map("address", func(w ValueWriter) {
+			w.Str("city", "Portland")
+			w.Str("zip", "97201")
+		})
+	})
+	if err != nil {
+		t.Fatalf("Put() error %s", err)
+	}
+
+	projected := table.Project(Project("name", "address.city"))
+	check := func(method string, r ValueReader) {
+		if got, want := r.Str("name"), "Ryan"; got != want {
+			t.Errorf("%s name got %#v, want %#v", method, got, want)
+		}
+		if got, want := r.At("address").Str("city"), "Portland"; got != want {
+			t.Errorf("%s city got %#v, want %#v", method, got, want)
+		}
+		if r.Has("status") || r.At("address").Has("zip") {
+			t.Errorf("%s got unprojected attributes", method)
+		}
+	}
+
+	r, err := projected.Get(func(w ValueWriter) { w.Str("str", "k1") })
+	if err != nil {
+		t.Fatalf("Get() error %s", err)
+	}
+	check("Get()", r)
+
+	rows, _, err := projected.RowsE()
+	if err != nil || len(rows) != 1 {
+		t.Fatalf("RowsE() got (%#v, %v)", rows, err)
+	}
+	check("RowsE()", rows[0])
+
+	rows, _, err = projected.QueryE(Key("str").Eq("k1"))
+	if err != nil || len(rows) != 1 {
+		t.Fatalf("QueryE() got (%#v, %v)", rows, err)
+	}
+	check("QueryE()", rows[0])
+
+	if got, want := projected.RowCount(), 1; got != want {
+		t.Errorf("RowCount() got %d, want %d", got, want)
+	}
+}
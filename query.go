@@ -0,0 +1,182 @@
+package dynamis
+
+import (
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// KeyAttr names a key attribute, and builds the conditions on it that choose
+// the items read by a query.
+type KeyAttr string
+
+// Key names a key attribute. For example, Key("user_id").Eq(id) matches a
+// partition key and Key("date").Between(start, end) a range of sort keys.
+func Key(name string) KeyAttr {
+	return KeyAttr(name)
+}
+
+// KeyCondition is a condition on a key attribute. Its values are encoded like
+// ValueWriter.Encode when the query is built, so they may be strings, numbers,
+// binary values, or any type with a registered Codec.
+type KeyCondition struct {
+	name string
+	op   string
+	vals []interface{}
+}
+
+// Eq matches keys equal to v. It is the only condition allowed on a partition
+// key.
+func (k KeyAttr) Eq(v interface{}) KeyCondition {
+	return KeyCondition{string(k), "=", []interface{}{v}}
+}
+
+// Lt matches sort keys less than v.
+func (k KeyAttr) Lt(v interface{}) KeyCondition {
+	return KeyCondition{string(k), "<", []interface{}{v}}
+}
+
+// Le matches sort keys less than or equal to v.
+func (k KeyAttr) Le(v interface{}) KeyCondition {
+	return KeyCondition{string(k), "<=", []interface{}{v}}
+}
+
+// Gt matches sort keys greater than v.
+func (k KeyAttr) Gt(v interface{}) KeyCondition {
+	return KeyCondition{string(k), ">", []interface{}{v}}
+}
+
+// Ge matches sort keys greater than or equal to v.
+func (k KeyAttr) Ge(v interface{}) KeyCondition {
+	return KeyCondition{string(k), ">=", []interface{}{v}}
+}
+
+// Between matches sort keys from lo to hi, inclusive.
+func (k KeyAttr) Between(lo, hi interface{}) KeyCondition {
+	return KeyCondition{string(k), "BETWEEN", []interface{}{lo, hi}}
+}
+
+// BeginsWith matches string or binary sort keys that start with prefix.
+func (k KeyAttr) BeginsWith(prefix interface{}) KeyCondition {
+	return KeyCondition{string(k), "begins_with", []interface{}{prefix}}
+}
+
+// build returns the condition as a key condition expression.
+func (c KeyCondition) build(b *exprBuilder) (string, error) {
+	if c.name == "" {
+		return "", errors.New("dynamis: key condition has no attribute name")
+	}
+	// Key attributes are always top-level, so the name is not a path.
+	name := b.attr(c.name)
+	vals := make([]string, len(c.vals))
+	for i, v := range c.vals {
+		var err error
+		if vals[i], err = b.value(c.name, v); err != nil {
+			return "", err
+		}
+	}
+	switch c.op {
+	case "BETWEEN":
+		return name + " BETWEEN " + vals[0] + " AND " + vals[1], nil
+	case "begins_with":
+		return "begins_with(" + name + ", " + vals[0] + ")", nil
+	default:
+		return name + " " + c.op + " " + vals[0], nil
+	}
+}
+
+// keyCondition builds the key condition expression for a partition key
+// condition and an optional sort key condition.
+func keyCondition(b *exprBuilder, pk KeyCondition, sk []KeyCondition) (string, error) {
+	if pk.op != "=" {
+		return "", errors.New("dynamis: partition key condition must be Eq")
+	}
+	if len(sk) > 1 {
+		return "", errors.New("dynamis: query takes at most one sort key condition")
+	}
+	expr, err := pk.build(b)
+	if err != nil {
+		return "", err
+	}
+	for _, c := range sk {
+		skExpr, err := c.build(b)
+		if err != nil {
+			return "", err
+		}
+		expr += " AND " + skExpr
+	}
+	return expr, nil
+}
+
+// queryRows calls f with each row returned by the query, following
+// LastEvaluatedKey until the query is complete. Every row shares the definer.
+// If f returns an error, the query stops and returns it.
+func queryRows(db *dynamodb.DynamoDB, input *dynamodb.QueryInput, vd valueDefiner, f func(Row) error) error {
+	return pageRows(func(page func([]map[string]*dynamodb.AttributeValue) bool) error {
+		return db.QueryPages(input, func(out *dynamodb.QueryOutput, last bool) bool {
+			return page(out.Items)
+		})
+	}, vd, f)
+}
+
+// Query returns a Row accessor for every item with the partition key matched
+// by pk, optionally narrowed by one condition on the sort key. Rows are in
+// sort key order. If an error occurs, the rows will be nil. The returned
+// ValueDefiner can be used to define access to custom types across all rows.
+func (t Table) Query(pk KeyCondition, sk ...KeyCondition) ([]Row, ValueDefiner) {
+	rows, vd, err := t.QueryE(pk, sk...)
+	if err != nil {
+		return nil, nil
+	}
+	return rows, vd
+}
+
+// QueryE is like Query, but returns the error from building the query or from
+// DynamoDB.
+func (t Table) QueryE(pk KeyCondition, sk ...KeyCondition) ([]Row, ValueDefiner, error) {
+	input, err := t.queryInput(pk, sk)
+	if err != nil {
+		return nil, nil, err
+	}
+	return queryAll(t.db, input)
+}
+
+// queryInput returns the input to query the table.
+func (t Table) queryInput(pk KeyCondition, sk []KeyCondition) (*dynamodb.QueryInput, error) {
+	b := newExprBuilder(DefaultCodecs)
+	expr, err := keyCondition(b, pk, sk)
+	if err != nil {
+		return nil, err
+	}
+	projection, err := t.projection.apply(b)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := t.filterExpr(b)
+	if err != nil {
+		return nil, err
+	}
+	return &dynamodb.QueryInput{
+		TableName:                 aws.String(t.tableName),
+		KeyConditionExpression:    aws.String(expr),
+		ProjectionExpression:      projection,
+		FilterExpression:          filter,
+		ExpressionAttributeNames:  b.attrNames(),
+		ExpressionAttributeValues: b.attrValues(),
+	}, nil
+}
+
+// queryAll returns every row returned by the query, sharing one definer.
+func queryAll(db *dynamodb.DynamoDB, input *dynamodb.QueryInput) ([]Row, ValueDefiner, error) {
+	vd := newValueDefiner()
+	rows := []Row{}
+	err := queryRows(db, input, vd, func(row Row) error {
+		rows = append(rows, row)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return rows, vd, nil
+}
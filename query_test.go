@@ -0,0 +1,242 @@
+package dynamis
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestKeyCondition(t *testing.T) {
+	tests := []struct {
+		pk     KeyCondition
+		sk     []KeyCondition
+		want   string
+		names  map[string]*string
+		values map[string]*dynamodb.AttributeValue
+		err    bool
+	}{
+		{
+			pk:   Key("user").Eq("u1"),
+			want: "#n0 = :v0",
+			values: map[string]*dynamodb.AttributeValue{
+				":v0": {S: aws.String("u1")},
+			},
+		},
+		{
+			pk:   Key("user").Eq("u1"),
+			sk:   []KeyCondition{Key("n").Lt(3)},
+			want: "#n0 = :v0 AND #n1 < :v1",
+			values: map[string]*dynamodb.AttributeValue{
+				":v0": {S: aws.String("u1")},
+				":v1": {N: aws.String("3")},
+			},
+		},
+		{
+			pk:   Key("user").Eq("u1"),
+			sk:   []KeyCondition{Key("n").Le(3)},
+			want: "#n0 = :v0 AND #n1 <= :v1",
+		},
+		{
+			pk:   Key("user").Eq("u1"),
+			sk:   []KeyCondition{Key("n").Gt(3)},
+			want: "#n0 = :v0 AND #n1 > :v1",
+		},
+		{
+			pk:   Key("user").Eq("u1"),
+			sk:   []KeyCondition{Key("n").Ge(3)},
+			want: "#n0 = :v0 AND #n1 >= :v1",
+		},
+		{
+			pk:   Key("user").Eq("u1"),
+			sk:   []KeyCondition{Key("date").Between("2015-01", "2015-12")},
+			want: "#n0 = :v0 AND #n1 BETWEEN :v1 AND :v2",
+			values: map[string]*dynamodb.AttributeValue{
+				":v0": {S: aws.String("u1")},
+				":v1": {S: aws.String("2015-01")},
+				":v2": {S: aws.String("2015-12")},
+			},
+		},
+		{
+			pk:   Key("user").Eq("u1"),
+			sk:   []KeyCondition{Key("date").BeginsWith("2015-")},
+			want: "#n0 = :v0 AND begins_with(#n1, :v1)",
+		},
+		{
+			// Key names are used as they are, not parsed as paths.
+			pk:   Key("user.id").Eq("u1"),
+			sk:   []KeyCondition{Key("n[0]").Eq(1)},
+			want: "#n0 = :v0 AND #n1 = :v1",
+			names: map[string]*string{
+				"#n0": aws.String("user.id"),
+				"#n1": aws.String("n[0]"),
+			},
+		},
+		{
+			// Partition keys must use Eq.
+			pk:  Key("user").Gt("u1"),
+			err: true,
+		},
+		{
+			// Only one sort key condition.
+			pk:  Key("user").Eq("u1"),
+			sk:  []KeyCondition{Key("a").Eq(1), Key("b").Eq(2)},
+			err: true,
+		},
+		{
+			// Empty values can't be matched.
+			pk:  Key("user").Eq(""),
+			err: true,
+		},
+		{
+			pk:  Key("").Eq("u1"),
+			err: true,
+		},
+		{
+			pk:  KeyCondition{},
+			err: true,
+		},
+	}
+	for i, test := range tests {
+		b := newExprBuilder(DefaultCodecs)
+		got, err := keyCondition(b, test.pk, test.sk)
+		if (err != nil) != test.err {
+			t.Errorf("%d keyCondition() error %v", i, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%d keyCondition() got %q, want %q", i, got, test.want)
+		}
+		if test.names != nil && !reflect.DeepEqual(b.attrNames(), test.names) {
+			t.Errorf("%d keyCondition() names got %#v, want %#v", i, b.attrNames(), test.names)
+		}
+		if test.values != nil && !reflect.DeepEqual(b.attrValues(), test.values) {
+			t.Errorf("%d keyCondition() values got %#v, want %#v", i, b.attrValues(), test.values)
+		}
+	}
+}
+
+// createQueryTable creates a table keyed by "user" and "n", with a row for
+// each n under users "a" and "b".
+func createQueryTable(t table, n int) error {
+	_, err := t.db.CreateTable(&dynamodb.CreateTableInput{
+		TableName: aws.String(t.name),
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{
+				AttributeName: aws.String("user"),
+				AttributeType: aws.String("S"),
+			},
+			{
+				AttributeName: aws.String("n"),
+				AttributeType: aws.String("N"),
+			},
+		},
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{
+				AttributeName: aws.String("user"),
+				KeyType:       aws.String("HASH"),
+			},
+			{
+				AttributeName: aws.String("n"),
+				KeyType:       aws.String("RANGE"),
+			},
+		},
+		ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+			ReadCapacityUnits:  aws.Int64(1),
+			WriteCapacityUnits: aws.Int64(1),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	for _, user := range []string{"a", "b"} {
+		for i := 0; i < n; i++ {
+			item := make(map[string]*dynamodb.AttributeValue)
+			SetStr(item, "user", user)
+			SetInt(item, "n", i)
+			_, err = t.db.PutItem(&dynamodb.PutItemInput{
+				TableName: aws.String(t.name),
+				Item:      item,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func TestTableQuery(t *testing.T) {
+	tbl := newTable()
+	if err := createQueryTable(tbl, 5); err != nil {
+		t.Fatalf("Failed initializing: %s", err)
+	}
+	table := CheckTable(tbl.db, tbl.name)
+	tests := []struct {
+		sk   []KeyCondition
+		want []int
+	}{
+		{want: []int{0, 1, 2, 3, 4}},
+		{sk: []KeyCondition{Key("n").Eq(2)}, want: []int{2}},
+		{sk: []KeyCondition{Key("n").Lt(2)}, want: []int{0, 1}},
+		{sk: []KeyCondition{Key("n").Le(2)}, want: []int{0, 1, 2}},
+		{sk: []KeyCondition{Key("n").Gt(2)}, want: []int{3, 4}},
+		{sk: []KeyCondition{Key("n").Ge(2)}, want: []int{2, 3, 4}},
+		{sk: []KeyCondition{Key("n").Between(1, 3)}, want: []int{1, 2, 3}},
+	}
+	for i, test := range tests {
+		rows, vd, err := table.QueryE(Key("user").Eq("a"), test.sk...)
+		if err != nil || vd == nil {
+			t.Errorf("%d QueryE() error %v", i, err)
+			continue
+		}
+		got := []int{}
+		for _, row := range rows {
+			if row.Str("user") != "a" {
+				t.Errorf("%d QueryE() got row %#v", i, row)
+			}
+			got = append(got, row.Int("n"))
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%d QueryE() got %#v, want %#v", i, got, test.want)
+		}
+	}
+
+	// Bad conditions and missing tables return nil.
+	if rows, vd := table.Query(Key("user").Gt("a")); rows != nil || vd != nil {
+		t.Errorf("Query(Gt) got (%#v, %#v)", rows, vd)
+	}
+	if rows, vd := CheckTable(tbl.db, "missing").Query(Key("user").Eq("a")); rows != nil || vd != nil {
+		t.Errorf("Query(missing) got (%#v, %#v)", rows, vd)
+	}
+}
+
+func TestTableQueryPaginated(t *testing.T) {
+	tbl := newTable()
+	if err := createQueryTable(tbl, 3); err != nil {
+		t.Fatalf("Failed initializing: %s", err)
+	}
+	// A limit of one row forces a page per row.
+	var got []int
+	err := queryRows(tbl.db, &dynamodb.QueryInput{
+		TableName:              aws.String(tbl.name),
+		KeyConditionExpression: aws.String("#u = :u"),
+		ExpressionAttributeNames: map[string]*string{
+			"#u": aws.String("user"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":u": {S: aws.String("b")},
+		},
+		Limit: aws.Int64(1),
+	}, newValueDefiner(), func(row Row) error {
+		got = append(got, row.Int("n"))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("queryRows() error %s", err)
+	}
+	if want := []int{0, 1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("queryRows() got %#v, want %#v", got, want)
+	}
+}
@@ -2,11 +2,17 @@ package dynamis
 
 import (
 	"fmt"
+	"math/big"
+	"reflect"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 )
 
-// ValueReader defines access to different types of value.
+// ValueReader defines access to different types of value. Nested readers
+// returned by At, Index and Path share custom definitions with their parent,
+// and read zero values if the nested map is missing or has another type.
 type ValueReader interface {
 
 	// Str returns a string value from the item.
@@ -15,10 +21,92 @@ type ValueReader interface {
 	// Int returns an int value from the item.
 	Int(key string) int
 
+	// Int64 returns an int64 value from the item.
+	Int64(key string) int64
+
+	// Uint64 returns a uint64 value from the item.
+	Uint64(key string) uint64
+
+	// Float64 returns a float64 value from the item.
+	Float64(key string) float64
+
+	// Bool returns a bool value from the item.
+	Bool(key string) bool
+
+	// Has returns true if the item contains key, even if its value is NULL.
+	Has(key string) bool
+
+	// IsNull returns true if the value at key is an explicit NULL.
+	IsNull(key string) bool
+
+	// BigInt returns an arbitrary-precision integer value from the item.
+	BigInt(key string) *big.Int
+
+	// BigFloat returns an arbitrary-precision float value from the item.
+	BigFloat(key string) *big.Float
+
+	// Num returns a number value from the item exactly as stored.
+	Num(key string) Decimal
+
+	// Bytes returns a binary value from the item.
+	Bytes(key string) []byte
+
+	// Time returns a time value from the item, stored as an RFC3339 string
+	// or a number of Unix seconds. Times stored with UnixMillis must be read
+	// with TimeLayout.
+	Time(key string) time.Time
+
+	// TimeLayout returns a time value stored with a layout from the item.
+	TimeLayout(key string, layout string) time.Time
+
+	// Duration returns a duration value from the item.
+	Duration(key string) time.Duration
+
+	// StrSet returns the sorted members of a string set from the item.
+	StrSet(key string) []string
+
+	// IntSet returns the sorted members of a number set from the item.
+	IntSet(key string) []int
+
+	// BytesSet returns the sorted members of a binary set from the item.
+	BytesSet(key string) [][]byte
+
+	// At returns a reader over the nested map stored at key.
+	At(key string) ValueReader
+
+	// Index returns a reader over the nested map stored at position i of the
+	// list at key.
+	Index(key string, i int) ValueReader
+
+	// Elem returns a reader over the element at position i of the list at
+	// key, which may be a scalar. Read the element with ElemKey, as in
+	// r.Elem("tags", 0).Str(dynamis.ElemKey).
+	Elem(key string, i int) ValueReader
+
+	// Len returns the number of elements in the list or map at key.
+	Len(key string) int
+
+	// Path returns a reader over the nested map found by following a
+	// document path such as "address" or "events[3]".
+	Path(path string) ValueReader
+
+	// Value returns a reader over the value found by following a document
+	// path such as "address.city" or "tags[0]", which may be a scalar. Read
+	// the value with ElemKey, as with Elem.
+	Value(path string) ValueReader
+
 	// Get returns the value from a custom-defined field.
 	Get(key string) interface{}
 
-	// ValueDefiner provides Def()
+	// Decode reads the value at key into the value pointed to by v, using a
+	// registered Codec for its type if there is one. If anything goes wrong,
+	// v is set to its zero value.
+	Decode(key string, v interface{})
+
+	// Strict returns a StrictValueReader over the same item.
+	Strict() StrictValueReader
+
+	// ValueDefiner provides Def() and DefE()
 	ValueDefiner
 }
 
@@ -26,32 +114,70 @@ type ValueReader interface {
 type ValueDefiner interface {
 	// Def defines a custom conversion, accessible via Get.
 	Def(key string, f DefFunc)
+
+	// DefE defines a custom conversion that can fail, accessible via Get
+	// and GetE.
+	DefE(key string, f DefEFunc)
 }
 
 // DefFunc is the handler for custom types.
 type DefFunc func(ValueReader) interface{}
 
+// DefEFunc is the handler for custom types that can report an error.
+type DefEFunc func(StrictValueReader) (interface{}, error)
+
 type valueDefiner struct {
-	defs map[string]DefFunc
+	defs   map[string]DefEFunc
+	codecs *Codecs
 }
 
 func newValueDefiner() valueDefiner {
-	return valueDefiner{make(map[string]DefFunc)}
+	return valueDefiner{make(map[string]DefEFunc), DefaultCodecs}
 }
 
 func (r valueDefiner) Def(key string, f DefFunc) {
+	r.defs[key] = func(sr StrictValueReader) (interface{}, error) {
+		return f(sr.Lenient()), nil
+	}
+}
+func (r valueDefiner) DefE(key string, f DefEFunc) {
 	r.defs[key] = f
 }
-func (r valueDefiner) call(key string, vr ValueReader) interface{} {
+func (r valueDefiner) call(key string, vr valueReader) interface{} {
 	if f, ok := r.defs[key]; ok {
-		return f(vr)
+		v, _ := f(strictValueReader{vr})
+		return v
 	}
 	panic(fmt.Sprintf("Missing def for: %s", key))
 }
+func (r valueDefiner) callE(key string, vr valueReader) (interface{}, error) {
+	if f, ok := r.defs[key]; ok {
+		return f(strictValueReader{vr})
+	}
+	return nil, ErrUndefined
+}
 
 type valueReader struct {
 	item map[string]*dynamodb.AttributeValue
 	def  valueDefiner
+	path string
+}
+
+// nested returns a reader over a nested item that shares definitions with r.
+func (r valueReader) nested(item map[string]*dynamodb.AttributeValue, path string) valueReader {
+	return valueReader{item: item, def: r.def, path: path}
+}
+
+// ElemKey is the key that reads the value itself from the readers returned by
+// Elem and Value.
+const ElemKey = ""
+
+// elem returns a reader over a single value, stored at ElemKey.
+func (r valueReader) elem(val *dynamodb.AttributeValue, path string) valueReader {
+	if val == nil {
+		return r.nested(nil, path)
+	}
+	return r.nested(map[string]*dynamodb.AttributeValue{ElemKey: val}, path)
 }
 
 func (r valueReader) Str(key string) string {
@@ -60,30 +186,432 @@ func (r valueReader) Str(key string) string {
 func (r valueReader) Int(key string) int {
 	return Int(r.item, key)
 }
+func (r valueReader) Int64(key string) int64 {
+	return Int64(r.item, key)
+}
+func (r valueReader) Uint64(key string) uint64 {
+	return Uint64(r.item, key)
+}
+func (r valueReader) Float64(key string) float64 {
+	return Float64(r.item, key)
+}
+func (r valueReader) Bool(key string) bool {
+	return Bool(r.item, key)
+}
+func (r valueReader) Has(key string) bool {
+	return Has(r.item, key)
+}
+func (r valueReader) IsNull(key string) bool {
+	return IsNull(r.item, key)
+}
+func (r valueReader) BigInt(key string) *big.Int {
+	return BigInt(r.item, key)
+}
+func (r valueReader) BigFloat(key string) *big.Float {
+	return BigFloat(r.item, key)
+}
+func (r valueReader) Num(key string) Decimal {
+	return Num(r.item, key)
+}
+func (r valueReader) Bytes(key string) []byte {
+	return Bytes(r.item, key)
+}
+func (r valueReader) Time(key string) time.Time {
+	return Time(r.item, key)
+}
+func (r valueReader) TimeLayout(key string, layout string) time.Time {
+	return TimeLayout(r.item, key, layout)
+}
+func (r valueReader) Duration(key string) time.Duration {
+	return Duration(r.item, key)
+}
+func (r valueReader) StrSet(key string) []string {
+	return StrSet(r.item, key)
+}
+func (r valueReader) IntSet(key string) []int {
+	return IntSet(r.item, key)
+}
+func (r valueReader) BytesSet(key string) [][]byte {
+	return BytesSet(r.item, key)
+}
+func (r valueReader) At(key string) ValueReader {
+	return r.nested(Map(r.item, key), joinPath(r.path, key))
+}
+func (r valueReader) Index(key string, i int) ValueReader {
+	path := indexPath(joinPath(r.path, key), i)
+	list := List(r.item, key)
+	if i < 0 || i >= len(list) || list[i] == nil {
+		return r.nested(nil, path)
+	}
+	return r.nested(list[i].M, path)
+}
+func (r valueReader) Elem(key string, i int) ValueReader {
+	path := indexPath(joinPath(r.path, key), i)
+	list := List(r.item, key)
+	if i < 0 || i >= len(list) {
+		return r.elem(nil, path)
+	}
+	return r.elem(list[i], path)
+}
+func (r valueReader) Len(key string) int {
+	if l := List(r.item, key); l != nil {
+		return len(l)
+	}
+	return len(Map(r.item, key))
+}
+func (r valueReader) Path(path string) ValueReader {
+	full := joinPath(r.path, path)
+	elems, err := parsePath(path)
+	if err != nil {
+		return r.nested(nil, full)
+	}
+	if val, _, _ := lookupPath(r.item, elems); val != nil {
+		return r.nested(val.M, full)
+	}
+	return r.nested(nil, full)
+}
+func (r valueReader) Value(path string) ValueReader {
+	full := joinPath(r.path, path)
+	elems, err := parsePath(path)
+	if err != nil {
+		return r.elem(nil, full)
+	}
+	val, _, _ := lookupPath(r.item, elems)
+	return r.elem(val, full)
+}
 func (r valueReader) Def(key string, f DefFunc) {
 	r.def.Def(key, f)
 }
+func (r valueReader) DefE(key string, f DefEFunc) {
+	r.def.DefE(key, f)
+}
 func (r valueReader) Get(key string) interface{} {
 	return r.def.call(key, r)
 }
+func (r valueReader) Decode(key string, v interface{}) {
+	if err := r.def.codecs.decode(r.item, key, v, joinPath(r.path, key)); err != nil {
+		zeroValue(v)
+	}
+}
+func (r valueReader) Strict() StrictValueReader {
+	return strictValueReader{r}
+}
 
 // NewValueReader initializes a ValueReader over an item.
 func NewValueReader(item map[string]*dynamodb.AttributeValue) ValueReader {
-	return valueReader{item, newValueDefiner()}
+	return valueReader{item: item, def: newValueDefiner()}
+}
+
+// StrictValueReader reads the same values as ValueReader, but reports why a
+// value could not be read instead of returning its zero value. Every error is
+// a *ValueError holding the full path of the key.
+type StrictValueReader interface {
+
+	// StrE returns a string value from the item.
+	StrE(key string) (string, error)
+
+	// IntE returns an int value from the item.
+	IntE(key string) (int, error)
+
+	// Int64E returns an int64 value from the item.
+	Int64E(key string) (int64, error)
+
+	// Uint64E returns a uint64 value from the item.
+	Uint64E(key string) (uint64, error)
+
+	// Float64E returns a float64 value from the item.
+	Float64E(key string) (float64, error)
+
+	// BoolE returns a bool value from the item.
+	BoolE(key string) (bool, error)
+
+	// BigIntE returns an arbitrary-precision integer value from the item.
+	BigIntE(key string) (*big.Int, error)
+
+	// BigFloatE returns an arbitrary-precision float value from the item.
+	BigFloatE(key string) (*big.Float, error)
+
+	// NumE returns a number value from the item exactly as stored.
+	NumE(key string) (Decimal, error)
+
+	// BytesE returns a binary value from the item.
+	BytesE(key string) ([]byte, error)
+
+	// TimeE returns a time value from the item, stored as an RFC3339 string
+	// or a number of Unix seconds. A number too large to be seconds is an
+	// ErrRange.
+	TimeE(key string) (time.Time, error)
+
+	// TimeLayoutE returns a time value stored with a layout from the item.
+	TimeLayoutE(key string, layout string) (time.Time, error)
+
+	// DurationE returns a duration value from the item.
+	DurationE(key string) (time.Duration, error)
+
+	// StrSetE returns the sorted members of a string set from the item.
+	StrSetE(key string) ([]string, error)
+
+	// IntSetE returns the sorted members of a number set from the item.
+	IntSetE(key string) ([]int, error)
+
+	// BytesSetE returns the sorted members of a binary set from the item.
+	BytesSetE(key string) ([][]byte, error)
+
+	// AtE returns a reader over the nested map stored at key.
+	AtE(key string) (StrictValueReader, error)
+
+	// IndexE returns a reader over the nested map stored at position i of
+	// the list at key.
+	IndexE(key string, i int) (StrictValueReader, error)
+
+	// PathE returns a reader over the nested map found by following a
+	// document path.
+	PathE(path string) (StrictValueReader, error)
+
+	// ElemE returns a reader over the element at position i of the list at
+	// key, read with ElemKey.
+	ElemE(key string, i int) (StrictValueReader, error)
+
+	// ValueE returns a reader over the value found by following a document
+	// path, read with ElemKey.
+	ValueE(path string) (StrictValueReader, error)
+
+	// GetE returns the value from a custom-defined field.
+	GetE(key string) (interface{}, error)
+
+	// DecodeE reads the value at key into the value pointed to by v, using a
+	// registered Codec for its type if there is one.
+	DecodeE(key string, v interface{}) error
+
+	// Lenient returns a ValueReader over the same item.
+	Lenient() ValueReader
+
+	// ValueDefiner provides Def() and DefE()
+	ValueDefiner
+}
+
+// NewStrictValueReader initializes a StrictValueReader over an item.
+func NewStrictValueReader(item map[string]*dynamodb.AttributeValue) StrictValueReader {
+	return strictValueReader{valueReader{item: item, def: newValueDefiner()}}
+}
+
+type strictValueReader struct {
+	r valueReader
+}
+
+// err wraps an error from reading key in a ValueError.
+func (s strictValueReader) err(key string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &ValueError{joinPath(s.r.path, key), err}
+}
+
+func (s strictValueReader) StrE(key string) (string, error) {
+	v, err := strE(s.r.item, key)
+	return v, s.err(key, err)
+}
+func (s strictValueReader) IntE(key string) (int, error) {
+	v, err := intE(s.r.item, key)
+	return v, s.err(key, err)
+}
+func (s strictValueReader) Int64E(key string) (int64, error) {
+	v, err := int64E(s.r.item, key)
+	return v, s.err(key, err)
+}
+func (s strictValueReader) Uint64E(key string) (uint64, error) {
+	v, err := uint64E(s.r.item, key)
+	return v, s.err(key, err)
+}
+func (s strictValueReader) Float64E(key string) (float64, error) {
+	v, err := float64E(s.r.item, key)
+	return v, s.err(key, err)
+}
+func (s strictValueReader) BoolE(key string) (bool, error) {
+	v, err := boolE(s.r.item, key)
+	return v, s.err(key, err)
+}
+func (s strictValueReader) BigIntE(key string) (*big.Int, error) {
+	v, err := bigIntE(s.r.item, key)
+	return v, s.err(key, err)
+}
+func (s strictValueReader) BigFloatE(key string) (*big.Float, error) {
+	v, err := bigFloatE(s.r.item, key)
+	return v, s.err(key, err)
+}
+func (s strictValueReader) NumE(key string) (Decimal, error) {
+	v, err := numE(s.r.item, key)
+	return Decimal(v), s.err(key, err)
+}
+func (s strictValueReader) BytesE(key string) ([]byte, error) {
+	v, err := bytesE(s.r.item, key)
+	return v, s.err(key, err)
+}
+func (s strictValueReader) TimeE(key string) (time.Time, error) {
+	v, err := timeE(s.r.item, key, "")
+	return v, s.err(key, err)
+}
+func (s strictValueReader) TimeLayoutE(key string, layout string) (time.Time, error) {
+	v, err := timeE(s.r.item, key, layout)
+	return v, s.err(key, err)
+}
+func (s strictValueReader) DurationE(key string) (time.Duration, error) {
+	v, err := durationE(s.r.item, key)
+	return v, s.err(key, err)
+}
+func (s strictValueReader) StrSetE(key string) ([]string, error) {
+	v, err := strSetE(s.r.item, key)
+	return v, s.err(key, err)
+}
+func (s strictValueReader) IntSetE(key string) ([]int, error) {
+	v, err := intSetE(s.r.item, key)
+	return v, s.err(key, err)
+}
+func (s strictValueReader) BytesSetE(key string) ([][]byte, error) {
+	v, err := bytesSetE(s.r.item, key)
+	return v, s.err(key, err)
+}
+func (s strictValueReader) AtE(key string) (StrictValueReader, error) {
+	m, err := mapE(s.r.item, key)
+	return strictValueReader{s.r.nested(m, joinPath(s.r.path, key))}, s.err(key, err)
+}
+func (s strictValueReader) IndexE(key string, i int) (StrictValueReader, error) {
+	path := indexPath(joinPath(s.r.path, key), i)
+	empty := strictValueReader{s.r.nested(nil, path)}
+	list, err := listE(s.r.item, key)
+	if err != nil {
+		return empty, s.err(key, err)
+	}
+	if i < 0 || i >= len(list) || list[i] == nil {
+		return empty, &ValueError{path, ErrMissing}
+	}
+	if list[i].M == nil {
+		return empty, &ValueError{path, ErrType}
+	}
+	return strictValueReader{s.r.nested(list[i].M, path)}, nil
+}
+func (s strictValueReader) PathE(path string) (StrictValueReader, error) {
+	empty := strictValueReader{s.r.nested(nil, joinPath(s.r.path, path))}
+	elems, err := parsePath(path)
+	if err != nil {
+		return empty, s.err(path, err)
+	}
+	val, n, err := lookupPath(s.r.item, elems)
+	if err != nil {
+		return empty, s.err(formatPath(elems[:n+1]), err)
+	}
+	if val.M == nil {
+		return empty, s.err(path, ErrType)
+	}
+	return strictValueReader{s.r.nested(val.M, joinPath(s.r.path, path))}, nil
+}
+func (s strictValueReader) ElemE(key string, i int) (StrictValueReader, error) {
+	path := indexPath(joinPath(s.r.path, key), i)
+	empty := strictValueReader{s.r.elem(nil, path)}
+	list, err := listE(s.r.item, key)
+	if err != nil {
+		return empty, s.err(key, err)
+	}
+	if i < 0 || i >= len(list) || list[i] == nil {
+		return empty, &ValueError{path, ErrMissing}
+	}
+	return strictValueReader{s.r.elem(list[i], path)}, nil
+}
+func (s strictValueReader) ValueE(path string) (StrictValueReader, error) {
+	full := joinPath(s.r.path, path)
+	empty := strictValueReader{s.r.elem(nil, full)}
+	elems, err := parsePath(path)
+	if err != nil {
+		return empty, s.err(path, err)
+	}
+	val, n, err := lookupPath(s.r.item, elems)
+	if err != nil {
+		return empty, s.err(formatPath(elems[:n+1]), err)
+	}
+	return strictValueReader{s.r.elem(val, full)}, nil
+}
+func (s strictValueReader) GetE(key string) (interface{}, error) {
+	v, err := s.r.def.callE(key, s.r)
+	if _, ok := err.(*ValueError); err != nil && !ok {
+		err = s.err(key, err)
+	}
+	return v, err
+}
+func (s strictValueReader) DecodeE(key string, v interface{}) error {
+	return s.r.def.codecs.decode(s.r.item, key, v, joinPath(s.r.path, key))
+}
+func (s strictValueReader) Lenient() ValueReader {
+	return s.r
+}
+func (s strictValueReader) Def(key string, f DefFunc) {
+	s.r.def.Def(key, f)
+}
+func (s strictValueReader) DefE(key string, f DefEFunc) {
+	s.r.def.DefE(key, f)
 }
 
 // ValueWriter lets you easily set values in an DynamoDB AttributeValue map.
 type ValueWriter struct {
-	item map[string]*dynamodb.AttributeValue
+	item   map[string]*dynamodb.AttributeValue
+	codecs *Codecs
+	empty  EmptyPolicy
 }
 
 // NewValueWriter initializes a ValueWriter over an item.
 func NewValueWriter(item map[string]*dynamodb.AttributeValue) ValueWriter {
-	return ValueWriter{item}
+	return ValueWriter{item, DefaultCodecs, SkipEmpty}
+}
+
+// EmptyPolicy chooses what a ValueWriter does with empty values: empty strings,
+// binary values, sets, maps and lists, zero times, and nil or empty numbers
+// written with BigInt, BigFloat and Num.
+type EmptyPolicy int
+
+const (
+	// SkipEmpty does not store empty values. It is the default, and matches
+	// the Set helpers.
+	SkipEmpty EmptyPolicy = iota
+
+	// NullEmpty stores empty values as NULL attributes, which explicitly
+	// clears them.
+	NullEmpty
+
+	// StoreEmpty stores empty strings, binary values, maps and lists as they
+	// are. DynamoDB allows these in attributes that are not keys. Empty
+	// values that DynamoDB can't store, such as sets, are stored as NULL.
+	StoreEmpty
+)
+
+// WithEmpty returns a ValueWriter over the same item that handles empty values
+// with the policy. Nested maps and lists use the same policy.
+func (w ValueWriter) WithEmpty(policy EmptyPolicy) ValueWriter {
+	w.empty = policy
+	return w
+}
+
+// writeEmpty handles an empty value at key according to the policy. The
+// value is what StoreEmpty stores, or nil if DynamoDB can't store it.
+func (w ValueWriter) writeEmpty(key string, val *dynamodb.AttributeValue) {
+	switch {
+	case key == "" || w.empty == SkipEmpty:
+	case w.empty == StoreEmpty && val != nil:
+		w.item[key] = val
+	default:
+		SetNull(w.item, key)
+	}
+}
+
+// Null writes a NULL value to the item.
+func (w ValueWriter) Null(key string) {
+	SetNull(w.item, key)
 }
 
 // Str writes a string value to the item.
 func (w ValueWriter) Str(key string, val string) {
+	if val == "" {
+		w.writeEmpty(key, &dynamodb.AttributeValue{S: aws.String("")})
+		return
+	}
 	SetStr(w.item, key, val)
 }
 
@@ -91,3 +619,225 @@ func (w ValueWriter) Str(key string, val string) {
 func (w ValueWriter) Int(key string, val int) {
 	SetInt(w.item, key, val)
 }
+
+// Int64 writes an int64 value to the item.
+func (w ValueWriter) Int64(key string, val int64) {
+	SetInt64(w.item, key, val)
+}
+
+// Uint64 writes a uint64 value to the item.
+func (w ValueWriter) Uint64(key string, val uint64) {
+	SetUint64(w.item, key, val)
+}
+
+// Float64 writes a float64 value to the item, following the SetFloat64 rules.
+func (w ValueWriter) Float64(key string, val float64) {
+	SetFloat64(w.item, key, val)
+}
+
+// Bool writes a bool value to the item.
+func (w ValueWriter) Bool(key string, val bool) {
+	SetBool(w.item, key, val)
+}
+
+// BigInt writes an arbitrary-precision integer value to the item. It returns
+// an error if the value can't be represented by DynamoDB.
+func (w ValueWriter) BigInt(key string, val *big.Int) error {
+	if val == nil {
+		w.writeEmpty(key, nil)
+		return nil
+	}
+	return SetBigInt(w.item, key, val)
+}
+
+// BigFloat writes an arbitrary-precision float value to the item. It returns
+// an error if the value can't be represented by DynamoDB.
+func (w ValueWriter) BigFloat(key string, val *big.Float) error {
+	if val == nil {
+		w.writeEmpty(key, nil)
+		return nil
+	}
+	return SetBigFloat(w.item, key, val)
+}
+
+// Num writes a number value to the item exactly as written. It returns an
+// error if the value can't be represented by DynamoDB.
+func (w ValueWriter) Num(key string, val Decimal) error {
+	if val == "" {
+		w.writeEmpty(key, nil)
+		return nil
+	}
+	return SetNum(w.item, key, val)
+}
+
+// Bytes writes a binary value to the item.
+func (w ValueWriter) Bytes(key string, val []byte) {
+	if len(val) == 0 {
+		w.writeEmpty(key, &dynamodb.AttributeValue{B: []byte{}})
+		return
+	}
+	SetBytes(w.item, key, val)
+}
+
+// Time writes a time value to the item using a layout, which is UnixSeconds,
+// UnixMillis or a time.Format layout. An empty layout uses time.RFC3339Nano.
+func (w ValueWriter) Time(key string, t time.Time, layout string) {
+	if t.IsZero() {
+		w.writeEmpty(key, nil)
+		return
+	}
+	SetTime(w.item, key, t, layout)
+}
+
+// Duration writes a duration value to the item as nanoseconds.
+func (w ValueWriter) Duration(key string, d time.Duration) {
+	SetDuration(w.item, key, d)
+}
+
+// StrSet writes a string set to the item.
+func (w ValueWriter) StrSet(key string, val []string) {
+	if len(uniqueStrs(val)) == 0 {
+		w.writeEmpty(key, nil)
+		return
+	}
+	SetStrSet(w.item, key, val)
+}
+
+// IntSet writes a number set to the item.
+func (w ValueWriter) IntSet(key string, val []int) {
+	if len(val) == 0 {
+		w.writeEmpty(key, nil)
+		return
+	}
+	SetIntSet(w.item, key, val)
+}
+
+// BytesSet writes a binary set to the item.
+func (w ValueWriter) BytesSet(key string, val [][]byte) {
+	if len(uniqueBytes(val)) == 0 {
+		w.writeEmpty(key, nil)
+		return
+	}
+	SetBytesSet(w.item, key, val)
+}
+
+// Map writes a nested map to the item. The function builds the nested map with
+// its own ValueWriter, and if it writes nothing the map is treated as empty.
+func (w ValueWriter) Map(key string, f func(ValueWriter)) {
+	m := make(map[string]*dynamodb.AttributeValue)
+	f(ValueWriter{m, w.codecs, w.empty})
+	if len(m) == 0 {
+		w.writeEmpty(key, &dynamodb.AttributeValue{M: m})
+		return
+	}
+	SetMap(w.item, key, m)
+}
+
+// List writes a list to the item. The function appends elements with a
+// ListWriter, and if it appends nothing the list is treated as empty.
+func (w ValueWriter) List(key string, f func(ListWriter)) {
+	list := []*dynamodb.AttributeValue{}
+	f(ListWriter{&list, w.codecs, w.empty})
+	if len(list) == 0 {
+		w.writeEmpty(key, &dynamodb.AttributeValue{L: list})
+		return
+	}
+	SetList(w.item, key, list)
+}
+
+// Encode writes any value to the item, using a registered Codec for its type
+// if there is one. Values are converted as Marshal would convert a struct
+// field, and Encode returns an error if the type can't be converted. If the
+// value is empty it is handled with the writer's EmptyPolicy, while empty
+// values nested within it are skipped as Marshal skips them.
+func (w ValueWriter) Encode(key string, v interface{}) error {
+	item := make(map[string]*dynamodb.AttributeValue)
+	if err := w.codecs.encode(item, key, v, key); err != nil {
+		return err
+	}
+	if val, ok := item[key]; ok {
+		w.item[key] = val
+		return nil
+	}
+	w.writeEmpty(key, w.codecs.emptyValue(reflect.ValueOf(v)))
+	return nil
+}
+
+// ListWriter appends values to a DynamoDB list. It handles empty values with
+// the policy of the ValueWriter that created it, so by default empty strings,
+// empty maps and the like are not appended.
+type ListWriter struct {
+	list   *[]*dynamodb.AttributeValue
+	codecs *Codecs
+	empty  EmptyPolicy
+}
+
+// listKey is the key used to build list elements with the item helpers.
+const listKey = "v"
+
+// add builds an element with a ValueWriter and appends it if it was written.
+func (l ListWriter) add(f func(ValueWriter)) {
+	item := make(map[string]*dynamodb.AttributeValue)
+	f(ValueWriter{item, l.codecs, l.empty})
+	if val, ok := item[listKey]; ok {
+		*l.list = append(*l.list, val)
+	}
+}
+
+// Null appends a NULL value to the list.
+func (l ListWriter) Null() {
+	l.add(func(w ValueWriter) { w.Null(listKey) })
+}
+
+// Str appends a string value to the list.
+func (l ListWriter) Str(val string) {
+	l.add(func(w ValueWriter) { w.Str(listKey, val) })
+}
+
+// Int appends an int value to the list.
+func (l ListWriter) Int(val int) {
+	l.add(func(w ValueWriter) { w.Int(listKey, val) })
+}
+
+// Int64 appends an int64 value to the list.
+func (l ListWriter) Int64(val int64) {
+	l.add(func(w ValueWriter) { w.Int64(listKey, val) })
+}
+
+// Uint64 appends a uint64 value to the list.
+func (l ListWriter) Uint64(val uint64) {
+	l.add(func(w ValueWriter) { w.Uint64(listKey, val) })
+}
+
+// Float64 appends a float64 value to the list.
+func (l ListWriter) Float64(val float64) {
+	l.add(func(w ValueWriter) { w.Float64(listKey, val) })
+}
+
+// Bool appends a bool value to the list.
+func (l ListWriter) Bool(val bool) {
+	l.add(func(w ValueWriter) { w.Bool(listKey, val) })
+}
+
+// Bytes appends a binary value to the list.
+func (l ListWriter) Bytes(val []byte) {
+	l.add(func(w ValueWriter) { w.Bytes(listKey, val) })
+}
+
+// Map appends a nested map to the list.
+func (l ListWriter) Map(f func(ValueWriter)) {
+	l.add(func(w ValueWriter) { w.Map(listKey, f) })
+}
+
+// List appends a nested list to the list.
+func (l ListWriter) List(f func(ListWriter)) {
+	l.add(func(w ValueWriter) { w.List(listKey, f) })
+}
+
+// Encode appends any value to the list, using a registered Codec for its type
+// if there is one.
+func (l ListWriter) Encode(v interface{}) error {
+	var err error
+	l.add(func(w ValueWriter) { err = w.Encode(listKey, v) })
+	return err
+}
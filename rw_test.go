@@ -1,7 +1,11 @@
 package dynamis
 
 import (
+	"errors"
+	"math/big"
 	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -119,3 +123,848 @@ func TestValueWriter(t *testing.T) {
 		}
 	}
 }
+
+func TestValueReaderScalars(t *testing.T) {
+	r := NewValueReader(map[string]*dynamodb.AttributeValue{
+		"i64": {N: aws.String("-9000000000")},
+		"u64": {N: aws.String("18000000000000000000")},
+		"f64": {N: aws.String("1.5")},
+		"b":   {BOOL: aws.Bool(true)},
+		"bin": {B: []byte("hi")},
+	})
+	if got, want := r.Int64("i64"), int64(-9000000000); got != want {
+		t.Errorf("ValueReader#Int64() got %#v, want %#v", got, want)
+	}
+	if got, want := r.Uint64("u64"), uint64(18000000000000000000); got != want {
+		t.Errorf("ValueReader#Uint64() got %#v, want %#v", got, want)
+	}
+	if got, want := r.Float64("f64"), 1.5; got != want {
+		t.Errorf("ValueReader#Float64() got %#v, want %#v", got, want)
+	}
+	if got, want := r.Bool("b"), true; got != want {
+		t.Errorf("ValueReader#Bool() got %#v, want %#v", got, want)
+	}
+	if got, want := r.Bytes("bin"), []byte("hi"); !reflect.DeepEqual(got, want) {
+		t.Errorf("ValueReader#Bytes() got %#v, want %#v", got, want)
+	}
+	empty := NewValueReader(map[string]*dynamodb.AttributeValue{})
+	if empty.Int64("i64") != 0 || empty.Uint64("u64") != 0 || empty.Float64("f64") != 0 ||
+		empty.Bool("b") || empty.Bytes("bin") != nil {
+		t.Errorf("ValueReader zero values are not zero")
+	}
+}
+
+func TestValueWriterScalars(t *testing.T) {
+	item := make(map[string]*dynamodb.AttributeValue)
+	w := NewValueWriter(item)
+	w.Int64("i64", -9000000000)
+	w.Uint64("u64", 18000000000000000000)
+	w.Float64("f64", 1.5)
+	w.Bool("b", false)
+	w.Bytes("bin", []byte("hi"))
+	w.Bytes("nobin", nil)
+	want := map[string]*dynamodb.AttributeValue{
+		"i64": {N: aws.String("-9000000000")},
+		"u64": {N: aws.String("18000000000000000000")},
+		"f64": {N: aws.String("1.5")},
+		"b":   {BOOL: aws.Bool(false)},
+		"bin": {B: []byte("hi")},
+	}
+	if !reflect.DeepEqual(item, want) {
+		t.Errorf("ValueWriter got %#v, want %#v", item, want)
+	}
+}
+
+func TestValueReaderSets(t *testing.T) {
+	r := NewValueReader(map[string]*dynamodb.AttributeValue{
+		"ss": {SS: aws.StringSlice([]string{"b", "a"})},
+		"ns": {NS: aws.StringSlice([]string{"2", "1"})},
+		"bs": {BS: [][]byte{[]byte("b"), []byte("a")}},
+	})
+	if got, want := r.StrSet("ss"), []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ValueReader#StrSet() got %#v, want %#v", got, want)
+	}
+	if got, want := r.IntSet("ns"), []int{1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ValueReader#IntSet() got %#v, want %#v", got, want)
+	}
+	if got, want := r.BytesSet("bs"), [][]byte{[]byte("a"), []byte("b")}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ValueReader#BytesSet() got %#v, want %#v", got, want)
+	}
+}
+
+func TestValueWriterSets(t *testing.T) {
+	item := make(map[string]*dynamodb.AttributeValue)
+	w := NewValueWriter(item)
+	w.StrSet("ss", []string{"b", "a"})
+	w.IntSet("ns", []int{2, 1})
+	w.BytesSet("bs", [][]byte{[]byte("b"), []byte("a")})
+	w.StrSet("empty", []string{})
+	want := map[string]*dynamodb.AttributeValue{
+		"ss": {SS: aws.StringSlice([]string{"a", "b"})},
+		"ns": {NS: aws.StringSlice([]string{"1", "2"})},
+		"bs": {BS: [][]byte{[]byte("a"), []byte("b")}},
+	}
+	if !reflect.DeepEqual(item, want) {
+		t.Errorf("ValueWriter got %#v, want %#v", item, want)
+	}
+}
+
+func TestValueReaderNested(t *testing.T) {
+	r := NewValueReader(map[string]*dynamodb.AttributeValue{
+		"address": {M: map[string]*dynamodb.AttributeValue{
+			"city": {S: aws.String("Portland")},
+		}},
+		"events": {L: []*dynamodb.AttributeValue{
+			{M: map[string]*dynamodb.AttributeValue{"type": {S: aws.String("signup")}}},
+			{S: aws.String("not a map")},
+			{M: map[string]*dynamodb.AttributeValue{
+				"type": {S: aws.String("login")},
+				"n":    {N: aws.String("2")},
+			}},
+		}},
+	})
+	r.Def("upcity", func(vr ValueReader) interface{} {
+		return strings.ToUpper(vr.Str("city"))
+	})
+	tests := []struct {
+		reader ValueReader
+		key    string
+		want   string
+	}{
+		{r.At("address"), "city", "Portland"},
+		{r.At("events"), "city", ""},
+		{r.At("nope"), "city", ""},
+		{r.Index("events", 0), "type", "signup"},
+		{r.Index("events", 1), "type", ""},
+		{r.Index("events", 2), "type", "login"},
+		{r.Index("events", 3), "type", ""},
+		{r.Index("events", -1), "type", ""},
+		{r.Index("address", 0), "type", ""},
+		{r.Path("address"), "city", "Portland"},
+		{r.Path("events[2]"), "type", "login"},
+		{r.Path("events[9]"), "type", ""},
+		{r.Path("address.city"), "city", ""},
+		{r.Path("events["), "type", ""},
+	}
+	for i, test := range tests {
+		if got := test.reader.Str(test.key); got != test.want {
+			t.Errorf("%d nested Str() got %#v, want %#v", i, got, test.want)
+		}
+	}
+	if got, want := r.Path("events[2]").Int("n"), 2; got != want {
+		t.Errorf("nested Int() got %#v, want %#v", got, want)
+	}
+	if got, want := r.At("address").Get("upcity"), "PORTLAND"; got != want {
+		t.Errorf("nested Get() got %#v, want %#v", got, want)
+	}
+	if got, want := r.Len("events"), 3; got != want {
+		t.Errorf("Len(list) got %#v, want %#v", got, want)
+	}
+	if got, want := r.Len("address"), 1; got != want {
+		t.Errorf("Len(map) got %#v, want %#v", got, want)
+	}
+	if got, want := r.Len("nope"), 0; got != want {
+		t.Errorf("Len(missing) got %#v, want %#v", got, want)
+	}
+}
+
+func TestValueReaderElem(t *testing.T) {
+	item := map[string]*dynamodb.AttributeValue{
+		"tags": {L: []*dynamodb.AttributeValue{
+			{S: aws.String("red")},
+			{N: aws.String("7")},
+			{M: map[string]*dynamodb.AttributeValue{"type": {S: aws.String("signup")}}},
+		}},
+		"address": {M: map[string]*dynamodb.AttributeValue{
+			"city": {S: aws.String("Portland")},
+		}},
+	}
+	r := NewValueReader(item)
+	tests := []struct {
+		reader ValueReader
+		want   string
+		n      int
+	}{
+		{r.Elem("tags", 0), "red", 0},
+		{r.Elem("tags", 1), "", 7},
+		{r.Elem("tags", 3), "", 0},
+		{r.Elem("tags", -1), "", 0},
+		{r.Elem("address", 0), "", 0},
+		{r.Value("tags[0]"), "red", 0},
+		{r.Value("tags[1]"), "", 7},
+		{r.Value("address.city"), "Portland", 0},
+		{r.Value("address.zip"), "", 0},
+		{r.Value("tags["), "", 0},
+	}
+	for i, test := range tests {
+		if got := test.reader.Str(ElemKey); got != test.want {
+			t.Errorf("%d elem Str() got %#v, want %#v", i, got, test.want)
+		}
+		if got := test.reader.Int(ElemKey); got != test.n {
+			t.Errorf("%d elem Int() got %#v, want %#v", i, got, test.n)
+		}
+	}
+	if got, want := r.Elem("tags", 2).At(ElemKey).Str("type"), "signup"; got != want {
+		t.Errorf("elem At() got %#v, want %#v", got, want)
+	}
+
+	s := NewStrictValueReader(item)
+	errTests := []struct {
+		read func() (interface{}, error)
+		want interface{}
+		path string
+		err  error
+	}{
+		{
+			read: func() (interface{}, error) {
+				e, err := s.ElemE("tags", 0)
+				if err != nil {
+					return nil, err
+				}
+				return e.StrE(ElemKey)
+			},
+			want: "red",
+		},
+		{
+			read: func() (interface{}, error) {
+				e, err := s.ElemE("tags", 1)
+				if err != nil {
+					return nil, err
+				}
+				return e.StrE(ElemKey)
+			},
+			want: "",
+			path: "tags[1]",
+			err:  ErrType,
+		},
+		{
+			read: func() (interface{}, error) {
+				_, err := s.ElemE("tags", 3)
+				return nil, err
+			},
+			path: "tags[3]",
+			err:  ErrMissing,
+		},
+		{
+			read: func() (interface{}, error) {
+				_, err := s.ElemE("address", 0)
+				return nil, err
+			},
+			path: "address",
+			err:  ErrType,
+		},
+		{
+			read: func() (interface{}, error) {
+				v, err := s.ValueE("address.city")
+				if err != nil {
+					return nil, err
+				}
+				return v.StrE(ElemKey)
+			},
+			want: "Portland",
+		},
+		{
+			read: func() (interface{}, error) {
+				v, err := s.ValueE("tags[1]")
+				if err != nil {
+					return nil, err
+				}
+				return v.IntE(ElemKey)
+			},
+			want: 7,
+		},
+		{
+			read: func() (interface{}, error) {
+				_, err := s.ValueE("tags[5]")
+				return nil, err
+			},
+			path: "tags[5]",
+			err:  ErrMissing,
+		},
+	}
+	for i, test := range errTests {
+		got, err := test.read()
+		if test.err == nil {
+			if err != nil {
+				t.Errorf("%d got error %s", i, err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("%d got %#v, want %#v", i, got, test.want)
+			}
+			continue
+		}
+		verr, ok := err.(*ValueError)
+		if !ok {
+			t.Errorf("%d got error %#v, want *ValueError", i, err)
+			continue
+		}
+		if verr.Path != test.path || verr.Err != test.err {
+			t.Errorf("%d got error (%q, %v), want (%q, %v)", i, verr.Path, verr.Err, test.path, test.err)
+		}
+	}
+}
+
+func TestValueWriterNested(t *testing.T) {
+	item := make(map[string]*dynamodb.AttributeValue)
+	w := NewValueWriter(item)
+	w.Map("address", func(sub ValueWriter) {
+		sub.Str("city", "Portland")
+		sub.Str("street", "")
+	})
+	w.Map("empty", func(sub ValueWriter) {
+		sub.Str("city", "")
+	})
+	w.List("tags", func(l ListWriter) {
+		l.Str("a")
+		l.Str("")
+		l.Int(1)
+		l.Int64(2)
+		l.Uint64(3)
+		l.Float64(1.5)
+		l.Bool(true)
+		l.Bytes([]byte("b"))
+		l.Bytes(nil)
+		l.Map(func(sub ValueWriter) {
+			sub.Str("type", "login")
+		})
+		l.Map(func(sub ValueWriter) {})
+		l.List(func(l ListWriter) {
+			l.Str("nested")
+		})
+		l.List(func(l ListWriter) {
+			l.Str("")
+		})
+	})
+	w.List("none", func(l ListWriter) {
+		l.Str("")
+		l.Map(func(sub ValueWriter) {})
+	})
+	want := map[string]*dynamodb.AttributeValue{
+		"address": {M: map[string]*dynamodb.AttributeValue{
+			"city": {S: aws.String("Portland")},
+		}},
+		"tags": {L: []*dynamodb.AttributeValue{
+			{S: aws.String("a")},
+			{N: aws.String("1")},
+			{N: aws.String("2")},
+			{N: aws.String("3")},
+			{N: aws.String("1.5")},
+			{BOOL: aws.Bool(true)},
+			{B: []byte("b")},
+			{M: map[string]*dynamodb.AttributeValue{
+				"type": {S: aws.String("login")},
+			}},
+			{L: []*dynamodb.AttributeValue{
+				{S: aws.String("nested")},
+			}},
+		}},
+	}
+	if !reflect.DeepEqual(item, want) {
+		t.Errorf("ValueWriter got %#v, want %#v", item, want)
+	}
+}
+
+func TestStrictValueReader(t *testing.T) {
+	r := NewStrictValueReader(map[string]*dynamodb.AttributeValue{
+		"s":   {S: aws.String("hello")},
+		"n":   {N: aws.String("33")},
+		"bad": {N: aws.String("abc")},
+		"f":   {N: aws.String("1.5")},
+		"b":   {BOOL: aws.Bool(true)},
+		"bin": {B: []byte("hi")},
+		"ss":  {SS: aws.StringSlice([]string{"b", "a"})},
+		"ns":  {NS: aws.StringSlice([]string{"2", "x"})},
+		"bs":  {BS: [][]byte{[]byte("a")}},
+		"address": {M: map[string]*dynamodb.AttributeValue{
+			"city": {S: aws.String("Portland")},
+			"zip":  {S: aws.String("97201")},
+		}},
+		"events": {L: []*dynamodb.AttributeValue{
+			{M: map[string]*dynamodb.AttributeValue{"type": {S: aws.String("signup")}}},
+			{S: aws.String("not a map")},
+		}},
+	})
+	nested, err := r.AtE("address")
+	if err != nil {
+		t.Fatalf("AtE() error %s", err)
+	}
+	event, err := r.IndexE("events", 0)
+	if err != nil {
+		t.Fatalf("IndexE() error %s", err)
+	}
+	tests := []struct {
+		read func() (interface{}, error)
+		want interface{}
+		path string
+		err  error
+	}{
+		{
+			read: func() (interface{}, error) { return r.StrE("s") },
+			want: "hello",
+		},
+		{
+			read: func() (interface{}, error) { return r.StrE("nope") },
+			want: "",
+			path: "nope",
+			err:  ErrMissing,
+		},
+		{
+			read: func() (interface{}, error) { return r.StrE("n") },
+			want: "",
+			path: "n",
+			err:  ErrType,
+		},
+		{
+			read: func() (interface{}, error) { return r.IntE("n") },
+			want: 33,
+		},
+		{
+			read: func() (interface{}, error) { return r.Int64E("n") },
+			want: int64(33),
+		},
+		{
+			read: func() (interface{}, error) { return r.Uint64E("n") },
+			want: uint64(33),
+		},
+		{
+			read: func() (interface{}, error) { return r.Float64E("f") },
+			want: 1.5,
+		},
+		{
+			read: func() (interface{}, error) { return r.BoolE("b") },
+			want: true,
+		},
+		{
+			read: func() (interface{}, error) { return r.BoolE("s") },
+			want: false,
+			path: "s",
+			err:  ErrType,
+		},
+		{
+			read: func() (interface{}, error) { return r.BytesE("bin") },
+			want: []byte("hi"),
+		},
+		{
+			read: func() (interface{}, error) { return r.StrSetE("ss") },
+			want: []string{"a", "b"},
+		},
+		{
+			read: func() (interface{}, error) { return r.BytesSetE("bs") },
+			want: [][]byte{[]byte("a")},
+		},
+		{
+			read: func() (interface{}, error) { return nested.StrE("city") },
+			want: "Portland",
+		},
+		{
+			read: func() (interface{}, error) { return nested.IntE("zip") },
+			want: 0,
+			path: "address.zip",
+			err:  ErrType,
+		},
+		{
+			read: func() (interface{}, error) { return event.StrE("kind") },
+			want: "",
+			path: "events[0].kind",
+			err:  ErrMissing,
+		},
+		{
+			read: func() (interface{}, error) {
+				_, err := r.IndexE("events", 1)
+				return nil, err
+			},
+			path: "events[1]",
+			err:  ErrType,
+		},
+		{
+			read: func() (interface{}, error) {
+				_, err := r.IndexE("events", 5)
+				return nil, err
+			},
+			path: "events[5]",
+			err:  ErrMissing,
+		},
+		{
+			read: func() (interface{}, error) {
+				_, err := r.AtE("s")
+				return nil, err
+			},
+			path: "s",
+			err:  ErrType,
+		},
+		{
+			read: func() (interface{}, error) {
+				sub, err := r.PathE("events[0]")
+				if err != nil {
+					return nil, err
+				}
+				return sub.StrE("type")
+			},
+			want: "signup",
+		},
+		{
+			read: func() (interface{}, error) {
+				_, err := r.PathE("address.city.name")
+				return nil, err
+			},
+			path: "address.city.name",
+			err:  ErrType,
+		},
+		{
+			read: func() (interface{}, error) {
+				_, err := r.PathE("events[3].type")
+				return nil, err
+			},
+			path: "events[3]",
+			err:  ErrMissing,
+		},
+		{
+			read: func() (interface{}, error) { return r.GetE("nope") },
+			path: "nope",
+			err:  ErrUndefined,
+		},
+	}
+	for i, test := range tests {
+		got, err := test.read()
+		if test.err == nil {
+			if err != nil {
+				t.Errorf("%d got error %s", i, err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("%d got %#v, want %#v", i, got, test.want)
+			}
+			continue
+		}
+		verr, ok := err.(*ValueError)
+		if !ok {
+			t.Errorf("%d got error %#v, want *ValueError", i, err)
+			continue
+		}
+		if verr.Path != test.path || verr.Err != test.err {
+			t.Errorf("%d got error (%q, %v), want (%q, %v)", i, verr.Path, verr.Err, test.path, test.err)
+		}
+		if test.want != nil && !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%d got %#v, want %#v", i, got, test.want)
+		}
+	}
+
+	// Parse failures report the strconv error.
+	if _, err := r.IntE("bad"); err == nil {
+		t.Errorf("IntE(bad) want error")
+	} else if verr, ok := err.(*ValueError); !ok || verr.Path != "bad" {
+		t.Errorf("IntE(bad) got %#v", err)
+	} else if _, ok := verr.Err.(*strconv.NumError); !ok {
+		t.Errorf("IntE(bad) got %#v, want *strconv.NumError", verr.Err)
+	}
+
+	// Number sets return the valid members along with the error.
+	ns, err := r.IntSetE("ns")
+	if !reflect.DeepEqual(ns, []int{2}) || err == nil {
+		t.Errorf("IntSetE() got (%#v, %v)", ns, err)
+	}
+}
+
+func TestStrictValueReaderDefs(t *testing.T) {
+	r := NewStrictValueReader(map[string]*dynamodb.AttributeValue{
+		"date": {S: aws.String("2015-09-16")},
+		"bad":  {S: aws.String("yesterday")},
+	})
+	parse := func(key string) DefEFunc {
+		return func(sr StrictValueReader) (interface{}, error) {
+			s, err := sr.StrE(key)
+			if err != nil {
+				return nil, err
+			}
+			return time.Parse("2006-01-02", s)
+		}
+	}
+	r.DefE("date_t", parse("date"))
+	r.DefE("bad_t", parse("bad"))
+	r.DefE("missing_t", parse("missing"))
+	r.Def("lenient", func(vr ValueReader) interface{} {
+		return vr.Str("date")
+	})
+
+	if got, err := r.GetE("date_t"); err != nil || got != time.Date(2015, 9, 16, 0, 0, 0, 0, time.UTC) {
+		t.Errorf("GetE(date_t) got (%#v, %v)", got, err)
+	}
+	if got, err := r.GetE("lenient"); err != nil || got != "2015-09-16" {
+		t.Errorf("GetE(lenient) got (%#v, %v)", got, err)
+	}
+
+	// Errors from the definition are reported with the definition key.
+	_, err := r.GetE("bad_t")
+	if verr, ok := err.(*ValueError); !ok || verr.Path != "bad_t" {
+		t.Errorf("GetE(bad_t) got %#v", err)
+	}
+
+	// ValueErrors from reads within the definition keep their path.
+	_, err = r.GetE("missing_t")
+	if verr, ok := err.(*ValueError); !ok || verr.Path != "missing" || verr.Err != ErrMissing {
+		t.Errorf("GetE(missing_t) got %#v", err)
+	}
+
+	// The lenient reader shares the definitions and ignores errors.
+	if got := r.Lenient().Get("bad_t"); got != time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC) {
+		t.Errorf("Get(bad_t) got %#v", got)
+	}
+	if got, err := r.Lenient().Strict().GetE("date_t"); err != nil || got == nil {
+		t.Errorf("Strict().GetE(date_t) got (%#v, %v)", got, err)
+	}
+}
+
+func TestValueReaderWriterTime(t *testing.T) {
+	at := time.Date(2015, 9, 16, 1, 2, 3, 0, time.UTC)
+	item := make(map[string]*dynamodb.AttributeValue)
+	w := NewValueWriter(item)
+	w.Time("start", at, "")
+	w.Time("expires", at, UnixSeconds)
+	w.Time("created", at, UnixMillis)
+	w.Time("zero", time.Time{}, "")
+	w.Duration("ttl", time.Hour)
+
+	r := NewValueReader(item)
+	if got := r.Time("start"); !got.Equal(at) {
+		t.Errorf("Time(start) got %s, want %s", got, at)
+	}
+	if got := r.Time("expires"); !got.Equal(at) {
+		t.Errorf("Time(expires) got %s, want %s", got, at)
+	}
+	if got, want := r.TimeLayout("expires", UnixMillis), time.Unix(1442365, 323e6); !got.Equal(want) {
+		t.Errorf("TimeLayout(expires) got %s, want %s", got, want)
+	}
+	if got := r.Time("created"); !got.IsZero() {
+		t.Errorf("Time(created) got %s, want zero", got)
+	}
+	if got := r.TimeLayout("created", UnixMillis); !got.Equal(at) {
+		t.Errorf("TimeLayout(created) got %s, want %s", got, at)
+	}
+	if got := r.Time("zero"); !got.IsZero() {
+		t.Errorf("Time(zero) got %s, want zero", got)
+	}
+	if got := r.Duration("ttl"); got != time.Hour {
+		t.Errorf("Duration(ttl) got %s, want %s", got, time.Hour)
+	}
+
+	s := r.Strict()
+	if got, err := s.TimeE("start"); err != nil || !got.Equal(at) {
+		t.Errorf("TimeE(start) got (%s, %v)", got, err)
+	}
+	if _, err := s.TimeE("created"); !errors.Is(err, ErrRange) {
+		t.Errorf("TimeE(created) got %v, want ErrRange", err)
+	}
+	item["huge"] = &dynamodb.AttributeValue{N: aws.String("1e30")}
+	if _, err := s.TimeLayoutE("huge", UnixSeconds); !errors.Is(err, ErrRange) {
+		t.Errorf("TimeLayoutE(huge) got %v, want ErrRange", err)
+	}
+	if _, err := s.TimeLayoutE("start", UnixSeconds); err == nil {
+		t.Errorf("TimeLayoutE(start) want error")
+	}
+	if _, err := s.DurationE("start"); err == nil {
+		t.Errorf("DurationE(start) want error")
+	}
+	if got, err := s.DurationE("ttl"); err != nil || got != time.Hour {
+		t.Errorf("DurationE(ttl) got (%s, %v)", got, err)
+	}
+}
+
+func TestValueReaderWriterBigNumbers(t *testing.T) {
+	item := make(map[string]*dynamodb.AttributeValue)
+	w := NewValueWriter(item)
+	if err := w.BigInt("i", big.NewInt(7)); err != nil {
+		t.Fatalf("BigInt() error %s", err)
+	}
+	if err := w.BigFloat("f", big.NewFloat(0.5)); err != nil {
+		t.Fatalf("BigFloat() error %s", err)
+	}
+	if err := w.Num("d", "12.50"); err != nil {
+		t.Fatalf("Num() error %s", err)
+	}
+	if err := w.Num("bad", "1E+200"); err == nil {
+		t.Errorf("Num(bad) want error")
+	}
+	r := NewValueReader(item)
+	if got := r.BigInt("i"); got.Int64() != 7 {
+		t.Errorf("BigInt() got %s", got)
+	}
+	if got, _ := r.BigFloat("f").Float64(); got != 0.5 {
+		t.Errorf("BigFloat() got %v", got)
+	}
+	if got := r.Num("d"); got != "12.50" {
+		t.Errorf("Num() got %#v", got)
+	}
+	s := r.Strict()
+	if _, err := s.BigIntE("f"); err == nil {
+		t.Errorf("BigIntE(f) want error")
+	}
+	if _, err := s.BigFloatE("missing"); err == nil {
+		t.Errorf("BigFloatE(missing) want error")
+	}
+	if got, err := s.NumE("d"); err != nil || got != "12.50" {
+		t.Errorf("NumE(d) got (%#v, %v)", got, err)
+	}
+}
+
+func TestValueWriterEmpty(t *testing.T) {
+	null := &dynamodb.AttributeValue{NULL: aws.Bool(true)}
+	write := func(w ValueWriter) {
+		w.Str("s", "")
+		w.Bytes("b", nil)
+		w.StrSet("ss", []string{""})
+		w.IntSet("ns", nil)
+		w.BytesSet("bs", nil)
+		w.Time("t", time.Time{}, "")
+		w.BigInt("i", nil)
+		w.Num("d", "")
+		w.Map("m", func(ValueWriter) {})
+		w.List("l", func(l ListWriter) {
+			l.Str("")
+		})
+		w.Map("nested", func(w ValueWriter) {
+			w.Str("s", "")
+			w.Int("i", 1)
+		})
+		w.Str("", "")
+		w.Encode("es", "")
+		w.Encode("eb", []byte(nil))
+		w.Encode("em", map[string]string{"s": ""})
+		w.Encode("el", []int{})
+		w.Encode("et", time.Time{})
+		w.Encode("ep", (*string)(nil))
+		w.Encode("en", nil)
+		w.List("el2", func(l ListWriter) {
+			l.Encode("")
+			l.Encode(1)
+		})
+	}
+	tests := []struct {
+		policy EmptyPolicy
+		want   map[string]*dynamodb.AttributeValue
+	}{
+		{
+			policy: SkipEmpty,
+			want: map[string]*dynamodb.AttributeValue{
+				"nested": {M: map[string]*dynamodb.AttributeValue{
+					"i": {N: aws.String("1")},
+				}},
+				"el2": {L: []*dynamodb.AttributeValue{
+					{N: aws.String("1")},
+				}},
+			},
+		},
+		{
+			policy: NullEmpty,
+			want: map[string]*dynamodb.AttributeValue{
+				"s":  null,
+				"b":  null,
+				"ss": null,
+				"ns": null,
+				"bs": null,
+				"t":  null,
+				"i":  null,
+				"d":  null,
+				"m":  null,
+				"l": {L: []*dynamodb.AttributeValue{
+					null,
+				}},
+				"nested": {M: map[string]*dynamodb.AttributeValue{
+					"s": null,
+					"i": {N: aws.String("1")},
+				}},
+				"es": null,
+				"eb": null,
+				"em": null,
+				"el": null,
+				"et": null,
+				"ep": null,
+				"en": null,
+				"el2": {L: []*dynamodb.AttributeValue{
+					null,
+					{N: aws.String("1")},
+				}},
+			},
+		},
+		{
+			policy: StoreEmpty,
+			want: map[string]*dynamodb.AttributeValue{
+				"s":  {S: aws.String("")},
+				"b":  {B: []byte{}},
+				"ss": null,
+				"ns": null,
+				"bs": null,
+				"t":  null,
+				"i":  null,
+				"d":  null,
+				"m":  {M: map[string]*dynamodb.AttributeValue{}},
+				"l": {L: []*dynamodb.AttributeValue{
+					{S: aws.String("")},
+				}},
+				"nested": {M: map[string]*dynamodb.AttributeValue{
+					"s": {S: aws.String("")},
+					"i": {N: aws.String("1")},
+				}},
+				"es": {S: aws.String("")},
+				"eb": {B: []byte{}},
+				"em": {M: map[string]*dynamodb.AttributeValue{}},
+				"el": {L: []*dynamodb.AttributeValue{}},
+				"et": null,
+				"ep": null,
+				"en": null,
+				"el2": {L: []*dynamodb.AttributeValue{
+					{S: aws.String("")},
+					{N: aws.String("1")},
+				}},
+			},
+		},
+	}
+	for i, test := range tests {
+		item := make(map[string]*dynamodb.AttributeValue)
+		write(NewValueWriter(item).WithEmpty(test.policy))
+		if !reflect.DeepEqual(item, test.want) {
+			t.Errorf("%d ValueWriter got %#v, want %#v", i, item, test.want)
+		}
+	}
+}
+
+func TestValueReaderNull(t *testing.T) {
+	null := &dynamodb.AttributeValue{NULL: aws.Bool(true)}
+	item := make(map[string]*dynamodb.AttributeValue)
+	w := NewValueWriter(item)
+	w.Str("s", "v")
+	w.Null("n")
+	w.List("l", func(l ListWriter) {
+		l.Null()
+	})
+	r := NewValueReader(item)
+	tests := []struct {
+		key    string
+		has    bool
+		isNull bool
+	}{
+		{key: "s", has: true, isNull: false},
+		{key: "n", has: true, isNull: true},
+		{key: "missing", has: false, isNull: false},
+	}
+	for i, test := range tests {
+		if got := r.Has(test.key); got != test.has {
+			t.Errorf("%d Has(%q) got %v, want %v", i, test.key, got, test.has)
+		}
+		if got := r.IsNull(test.key); got != test.isNull {
+			t.Errorf("%d IsNull(%q) got %v, want %v", i, test.key, got, test.isNull)
+		}
+	}
+	if got, want := List(item, "l"), []*dynamodb.AttributeValue{null}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListWriter.Null() got %#v, want %#v", got, want)
+	}
+	if got := r.Str("n"); got != "" {
+		t.Errorf("Str(n) got %#v", got)
+	}
+	_, err := r.Strict().StrE("n")
+	if verr, ok := err.(*ValueError); !ok || verr.Path != "n" || verr.Err != ErrNull {
+		t.Errorf("StrE(n) got %#v", err)
+	}
+	_, err = r.Strict().StrE("missing")
+	if verr, ok := err.(*ValueError); !ok || verr.Err != ErrMissing {
+		t.Errorf("StrE(missing) got %#v", err)
+	}
+}
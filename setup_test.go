@@ -8,6 +8,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 )
 
@@ -40,7 +41,7 @@ type table struct {
 func newTable() table {
 	var (
 		cfg       = newDynamoTestConfig()
-		db        = dynamodb.New(cfg)
+		db        = dynamodb.New(session.Must(session.NewSession(cfg)))
 		tableName = fmt.Sprintf("users-%d", time.Now().UnixNano())
 	)
 	return table{db, tableName}
@@ -0,0 +1,154 @@
+package dynamis
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// Layouts for storing times as numbers rather than strings. Any other layout
+// is a time.Format layout and stores the time as a string.
+const (
+	// UnixSeconds stores a time as the number of seconds since the Unix
+	// epoch. This is the format DynamoDB expects for TTL attributes.
+	UnixSeconds = "unix"
+
+	// UnixMillis stores a time as the number of milliseconds since the Unix
+	// epoch. Read these times back with TimeLayout and UnixMillis; without
+	// the layout a number is read as seconds.
+	UnixMillis = "unixmilli"
+)
+
+// maxUnixSeconds bounds the numbers read as seconds without a layout. It is
+// around the year 5000, while milliseconds pass it for any time after 1973,
+// so a number above it was almost certainly not written as seconds.
+const maxUnixSeconds = 1e11
+
+// Time returns a time from a DynamoDB attribute value. A string is parsed as
+// RFC3339, with or without fractional seconds, and a number is read as
+// seconds since the Unix epoch. Numbers too large to be seconds, such as
+// times stored with UnixMillis, are not guessed at; read those with
+// TimeLayout. If anything goes wrong reading or parsing the value, the zero
+// time is returned.
+func Time(item map[string]*dynamodb.AttributeValue, key string) time.Time {
+	t, _ := timeE(item, key, "")
+	return t
+}
+
+// TimeLayout returns a time stored with a layout, which is UnixSeconds,
+// UnixMillis or a time.Parse layout. If anything goes wrong reading or
+// parsing the value, the zero time is returned.
+func TimeLayout(item map[string]*dynamodb.AttributeValue, key string, layout string) time.Time {
+	t, _ := timeE(item, key, layout)
+	return t
+}
+
+// Duration returns a duration stored as a number of nanoseconds. If anything
+// goes wrong reading or parsing the value, 0 is returned.
+func Duration(item map[string]*dynamodb.AttributeValue, key string) time.Duration {
+	d, _ := durationE(item, key)
+	return d
+}
+
+// SetTime stores a time attribute using a layout, which is UnixSeconds,
+// UnixMillis or a time.Format layout. An empty layout uses time.RFC3339Nano.
+// If the time is zero, it is not stored.
+func SetTime(item map[string]*dynamodb.AttributeValue, key string, t time.Time, layout string) {
+	if key != "" && !t.IsZero() {
+		item[key] = formatTime(t, layout)
+	}
+}
+
+// SetDuration stores a duration attribute as a number of nanoseconds.
+func SetDuration(item map[string]*dynamodb.AttributeValue, key string, d time.Duration) {
+	SetInt64(item, key, int64(d))
+}
+
+func timeE(item map[string]*dynamodb.AttributeValue, key string, layout string) (time.Time, error) {
+	val, err := attrE(item, key)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return parseTime(val, layout)
+}
+
+func durationE(item map[string]*dynamodb.AttributeValue, key string) (time.Duration, error) {
+	n, err := int64E(item, key)
+	return time.Duration(n), err
+}
+
+// formatTime converts a time into an attribute value using a layout.
+func formatTime(t time.Time, layout string) *dynamodb.AttributeValue {
+	switch layout {
+	case UnixSeconds:
+		return &dynamodb.AttributeValue{N: aws.String(strconv.FormatInt(t.Unix(), 10))}
+	case UnixMillis:
+		ms := t.Unix()*1000 + int64(t.Nanosecond())/int64(time.Millisecond)
+		return &dynamodb.AttributeValue{N: aws.String(strconv.FormatInt(ms, 10))}
+	case "":
+		layout = time.RFC3339Nano
+	}
+	return &dynamodb.AttributeValue{S: aws.String(t.Format(layout))}
+}
+
+// parseTime converts an attribute value into a time using a layout. An empty
+// layout accepts either an RFC3339 string or a number of Unix seconds. Times
+// read from numbers are in UTC.
+func parseTime(val *dynamodb.AttributeValue, layout string) (time.Time, error) {
+	switch layout {
+	case UnixSeconds, UnixMillis:
+		if val.N == nil {
+			return time.Time{}, ErrType
+		}
+		return parseUnix(*val.N, layout)
+	case "":
+		if val.N != nil {
+			return parseUnixSeconds(*val.N)
+		}
+		layout = time.RFC3339Nano
+	}
+	if val.S == nil {
+		return time.Time{}, ErrType
+	}
+	return time.Parse(layout, *val.S)
+}
+
+// parseUnixSeconds reads a number of seconds for a time stored without a
+// layout, rejecting numbers that are too large to be seconds.
+func parseUnixSeconds(n string) (time.Time, error) {
+	f, err := strconv.ParseFloat(n, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if math.Abs(f) >= maxUnixSeconds {
+		return time.Time{}, ErrRange
+	}
+	return parseUnix(n, UnixSeconds)
+}
+
+// parseUnix reads a number of seconds or milliseconds since the Unix epoch.
+// Fractional values are accepted and kept to the nearest nanosecond. Numbers
+// too large for a time are an ErrRange.
+func parseUnix(n string, layout string) (time.Time, error) {
+	if i, err := strconv.ParseInt(n, 10, 64); err == nil {
+		if layout == UnixMillis {
+			return time.Unix(i/1000, i%1000*int64(time.Millisecond)).UTC(), nil
+		}
+		return time.Unix(i, 0).UTC(), nil
+	}
+	f, err := strconv.ParseFloat(n, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if layout == UnixMillis {
+		f /= 1000
+	}
+	sec, frac := math.Modf(f)
+	if math.IsNaN(sec) || sec >= math.MaxInt64 || sec < math.MinInt64 {
+		return time.Time{}, ErrRange
+	}
+	return time.Unix(int64(sec), int64(frac*float64(time.Second))).UTC(), nil
+}
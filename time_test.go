@@ -0,0 +1,171 @@
+package dynamis
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestTime(t *testing.T) {
+	tests := []struct {
+		item   map[string]*dynamodb.AttributeValue
+		layout string
+		want   time.Time
+	}{
+		{
+			// Item is nil, returns the zero value.
+			item: nil,
+			want: time.Time{},
+		},
+		{
+			// RFC3339 with nanoseconds.
+			item: map[string]*dynamodb.AttributeValue{"k": {S: aws.String("2015-09-16T01:02:03.000000004Z")}},
+			want: time.Date(2015, 9, 16, 1, 2, 3, 4, time.UTC),
+		},
+		{
+			// RFC3339 without fractional seconds.
+			item: map[string]*dynamodb.AttributeValue{"k": {S: aws.String("2015-09-16T01:02:03Z")}},
+			want: time.Date(2015, 9, 16, 1, 2, 3, 0, time.UTC),
+		},
+		{
+			// Unix seconds by default for numbers.
+			item: map[string]*dynamodb.AttributeValue{"k": {N: aws.String("1442365323")}},
+			want: time.Date(2015, 9, 16, 1, 2, 3, 0, time.UTC),
+		},
+		{
+			// Unix seconds with a fraction.
+			item: map[string]*dynamodb.AttributeValue{"k": {N: aws.String("1442365323.5")}},
+			want: time.Date(2015, 9, 16, 1, 2, 3, 5e8, time.UTC),
+		},
+		{
+			// Unix milliseconds.
+			item:   map[string]*dynamodb.AttributeValue{"k": {N: aws.String("1442365323004")}},
+			layout: UnixMillis,
+			want:   time.Date(2015, 9, 16, 1, 2, 3, 4e6, time.UTC),
+		},
+		{
+			// Unix milliseconds before the epoch.
+			item:   map[string]*dynamodb.AttributeValue{"k": {N: aws.String("-1500")}},
+			layout: UnixMillis,
+			want:   time.Date(1969, 12, 31, 23, 59, 58, 5e8, time.UTC),
+		},
+		{
+			// Unix milliseconds without the layout are too large to be
+			// seconds, and return the zero value.
+			item: map[string]*dynamodb.AttributeValue{"k": {N: aws.String("1442365323004")}},
+			want: time.Time{},
+		},
+		{
+			// Numbers too large for a time return the zero value.
+			item:   map[string]*dynamodb.AttributeValue{"k": {N: aws.String("1e30")}},
+			layout: UnixSeconds,
+			want:   time.Time{},
+		},
+		{
+			item:   map[string]*dynamodb.AttributeValue{"k": {N: aws.String("-1e30")}},
+			layout: UnixMillis,
+			want:   time.Time{},
+		},
+		{
+			// Unix layout with a string returns the zero value.
+			item:   map[string]*dynamodb.AttributeValue{"k": {S: aws.String("1442365323")}},
+			layout: UnixSeconds,
+			want:   time.Time{},
+		},
+		{
+			// Custom layout.
+			item:   map[string]*dynamodb.AttributeValue{"k": {S: aws.String("2015-09-16")}},
+			layout: "2006-01-02",
+			want:   time.Date(2015, 9, 16, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			// Bad value returns the zero value.
+			item: map[string]*dynamodb.AttributeValue{"k": {S: aws.String("yesterday")}},
+			want: time.Time{},
+		},
+	}
+	for i, test := range tests {
+		var got time.Time
+		if test.layout == "" {
+			got = Time(test.item, "k")
+		} else {
+			got = TimeLayout(test.item, "k", test.layout)
+		}
+		if !got.Equal(test.want) {
+			t.Errorf("%d Time() got %s, want %s", i, got, test.want)
+		}
+	}
+}
+
+func TestSetTime(t *testing.T) {
+	at := time.Date(2015, 9, 16, 1, 2, 3, 4e6, time.UTC)
+	tests := []struct {
+		t      time.Time
+		layout string
+		want   map[string]*dynamodb.AttributeValue
+	}{
+		{
+			t:    time.Time{},
+			want: map[string]*dynamodb.AttributeValue{},
+		},
+		{
+			t:    at,
+			want: map[string]*dynamodb.AttributeValue{"k": {S: aws.String("2015-09-16T01:02:03.004Z")}},
+		},
+		{
+			t:      at,
+			layout: UnixSeconds,
+			want:   map[string]*dynamodb.AttributeValue{"k": {N: aws.String("1442365323")}},
+		},
+		{
+			t:      at,
+			layout: UnixMillis,
+			want:   map[string]*dynamodb.AttributeValue{"k": {N: aws.String("1442365323004")}},
+		},
+		{
+			t:      at,
+			layout: time.RFC822,
+			want:   map[string]*dynamodb.AttributeValue{"k": {S: aws.String("16 Sep 15 01:02 UTC")}},
+		},
+	}
+	for i, test := range tests {
+		item := map[string]*dynamodb.AttributeValue{}
+		SetTime(item, "k", test.t, test.layout)
+		if !reflect.DeepEqual(item, test.want) {
+			t.Errorf("%d SetTime() got %#v, want %#v", i, item, test.want)
+		}
+		if len(item) > 0 && test.layout != time.RFC822 {
+			if got := TimeLayout(item, "k", test.layout); !got.Equal(test.t.Truncate(stored(test.layout))) {
+				t.Errorf("%d TimeLayout() got %s, want %s", i, got, test.t)
+			}
+		}
+	}
+}
+
+// stored returns the precision kept by a layout.
+func stored(layout string) time.Duration {
+	switch layout {
+	case UnixSeconds:
+		return time.Second
+	case UnixMillis:
+		return time.Millisecond
+	}
+	return 0
+}
+
+func TestDuration(t *testing.T) {
+	item := map[string]*dynamodb.AttributeValue{}
+	SetDuration(item, "d", 90*time.Second)
+	if got, want := item["d"], (&dynamodb.AttributeValue{N: aws.String("90000000000")}); !reflect.DeepEqual(got, want) {
+		t.Errorf("SetDuration() got %#v, want %#v", got, want)
+	}
+	if got, want := Duration(item, "d"), 90*time.Second; got != want {
+		t.Errorf("Duration() got %s, want %s", got, want)
+	}
+	if got := Duration(item, "missing"); got != 0 {
+		t.Errorf("Duration(missing) got %s, want 0", got)
+	}
+}
@@ -0,0 +1,259 @@
+package dynamis
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// maxTransactItems is the most operations DynamoDB takes in one transaction.
+const maxTransactItems = 100
+
+// TransactWriter writes items in one or more tables atomically with
+// TransactWriteItems. Add operations, then call Run: either every operation
+// succeeds or none do. Each method returns the writer, so they can be
+// chained:
+//
+//	err := NewTransactWriter(db).
+//		PutIf("users", Cond.AttributeNotExists("user_id"), user).
+//		PutIf("emails", Cond.AttributeNotExists("email"), email).
+//		Run()
+//
+// Keys and items are written with ValueWriter, and an item can appear in only
+// one operation of a transaction.
+type TransactWriter struct {
+	ops []func() (*dynamodb.TransactWriteItem, error)
+
+	// write is replaced in tests.
+	write func(*dynamodb.TransactWriteItemsInput) (*dynamodb.TransactWriteItemsOutput, error)
+}
+
+// NewTransactWriter initializes an empty transaction.
+func NewTransactWriter(db *dynamodb.DynamoDB) *TransactWriter {
+	return &TransactWriter{write: db.TransactWriteItems}
+}
+
+func (tx *TransactWriter) add(op func() (*dynamodb.TransactWriteItem, error)) *TransactWriter {
+	tx.ops = append(tx.ops, op)
+	return tx
+}
+
+// Put writes the item built by the function, replacing any item with the same
+// key.
+func (tx *TransactWriter) Put(tableName string, item func(ValueWriter)) *TransactWriter {
+	return tx.put(tableName, nil, item)
+}
+
+// PutIf writes the item only if the existing item matches the condition.
+func (tx *TransactWriter) PutIf(tableName string, cond Condition, item func(ValueWriter)) *TransactWriter {
+	return tx.put(tableName, &cond, item)
+}
+
+func (tx *TransactWriter) put(tableName string, cond *Condition, item func(ValueWriter)) *TransactWriter {
+	return tx.add(func() (*dynamodb.TransactWriteItem, error) {
+		i, err := buildItem(item)
+		if err != nil {
+			return nil, err
+		}
+		b := newExprBuilder(DefaultCodecs)
+		expr, err := condExpr(b, cond)
+		if err != nil {
+			return nil, err
+		}
+		return &dynamodb.TransactWriteItem{Put: &dynamodb.Put{
+			TableName:                 aws.String(tableName),
+			Item:                      i,
+			ConditionExpression:       expr,
+			ExpressionAttributeNames:  b.attrNames(),
+			ExpressionAttributeValues: b.attrValues(),
+		}}, nil
+	})
+}
+
+// Update applies the update to the item with the key written by the function.
+func (tx *TransactWriter) Update(tableName string, key func(ValueWriter), u *UpdateBuilder) *TransactWriter {
+	return tx.update(tableName, nil, key, u)
+}
+
+// UpdateIf applies the update only if the existing item matches the
+// condition.
+func (tx *TransactWriter) UpdateIf(tableName string, cond Condition, key func(ValueWriter), u *UpdateBuilder) *TransactWriter {
+	return tx.update(tableName, &cond, key, u)
+}
+
+func (tx *TransactWriter) update(tableName string, cond *Condition, key func(ValueWriter), u *UpdateBuilder) *TransactWriter {
+	return tx.add(func() (*dynamodb.TransactWriteItem, error) {
+		input, err := Table{tableName: tableName}.updateInput(cond, key, u)
+		if err != nil {
+			return nil, err
+		}
+		return &dynamodb.TransactWriteItem{Update: &dynamodb.Update{
+			TableName:                 input.TableName,
+			Key:                       input.Key,
+			UpdateExpression:          input.UpdateExpression,
+			ConditionExpression:       input.ConditionExpression,
+			ExpressionAttributeNames:  input.ExpressionAttributeNames,
+			ExpressionAttributeValues: input.ExpressionAttributeValues,
+		}}, nil
+	})
+}
+
+// Delete removes the item with the key written by the function.
+func (tx *TransactWriter) Delete(tableName string, key func(ValueWriter)) *TransactWriter {
+	return tx.delete(tableName, nil, key)
+}
+
+// DeleteIf removes the item only if it matches the condition.
+func (tx *TransactWriter) DeleteIf(tableName string, cond Condition, key func(ValueWriter)) *TransactWriter {
+	return tx.delete(tableName, &cond, key)
+}
+
+func (tx *TransactWriter) delete(tableName string, cond *Condition, key func(ValueWriter)) *TransactWriter {
+	return tx.add(func() (*dynamodb.TransactWriteItem, error) {
+		k, err := buildKey(key)
+		if err != nil {
+			return nil, err
+		}
+		b := newExprBuilder(DefaultCodecs)
+		expr, err := condExpr(b, cond)
+		if err != nil {
+			return nil, err
+		}
+		return &dynamodb.TransactWriteItem{Delete: &dynamodb.Delete{
+			TableName:                 aws.String(tableName),
+			Key:                       k,
+			ConditionExpression:       expr,
+			ExpressionAttributeNames:  b.attrNames(),
+			ExpressionAttributeValues: b.attrValues(),
+		}}, nil
+	})
+}
+
+// ConditionCheck requires the item with the key written by the function to
+// match the condition, without writing it.
+func (tx *TransactWriter) ConditionCheck(tableName string, key func(ValueWriter), cond Condition) *TransactWriter {
+	return tx.add(func() (*dynamodb.TransactWriteItem, error) {
+		k, err := buildKey(key)
+		if err != nil {
+			return nil, err
+		}
+		b := newExprBuilder(DefaultCodecs)
+		expr, err := condExpr(b, &cond)
+		if err != nil {
+			return nil, err
+		}
+		return &dynamodb.TransactWriteItem{ConditionCheck: &dynamodb.ConditionCheck{
+			TableName:                 aws.String(tableName),
+			Key:                       k,
+			ConditionExpression:       expr,
+			ExpressionAttributeNames:  b.attrNames(),
+			ExpressionAttributeValues: b.attrValues(),
+		}}, nil
+	})
+}
+
+// condExpr builds an optional condition with a shared builder.
+func condExpr(b *exprBuilder, cond *Condition) (*string, error) {
+	if cond == nil {
+		return nil, nil
+	}
+	expr, err := cond.expr(b)
+	if err != nil {
+		return nil, err
+	}
+	return &expr, nil
+}
+
+// items builds every operation, checking DynamoDB's limits.
+func (tx *TransactWriter) items() ([]*dynamodb.TransactWriteItem, error) {
+	if len(tx.ops) == 0 {
+		return nil, errors.New("dynamis: transaction is empty")
+	}
+	if len(tx.ops) > maxTransactItems {
+		return nil, fmt.Errorf("dynamis: transaction has %d operations, the limit is %d", len(tx.ops), maxTransactItems)
+	}
+	items := make([]*dynamodb.TransactWriteItem, len(tx.ops))
+	for i, op := range tx.ops {
+		item, err := op()
+		if err != nil {
+			return nil, fmt.Errorf("dynamis: transaction operation %d: %w", i, err)
+		}
+		items[i] = item
+	}
+	return items, nil
+}
+
+// Run writes the transaction. If DynamoDB cancels it, Run returns a
+// *TransactionError explaining which operations caused it.
+func (tx *TransactWriter) Run() error {
+	items, err := tx.items()
+	if err != nil {
+		return err
+	}
+	_, err = tx.write(&dynamodb.TransactWriteItemsInput{
+		TransactItems: items,
+	})
+	return transactionErr(err)
+}
+
+// TransactionError reports why DynamoDB canceled a transaction, with an error
+// for each operation in the order they were added.
+type TransactionError struct {
+	// Errs holds an error for each operation, or nil for the operations that
+	// did not cause the cancellation. A condition that did not match is
+	// ErrConditionFailed.
+	Errs []error
+
+	// Err is the error from DynamoDB.
+	Err error
+}
+
+func (e *TransactionError) Error() string {
+	for i, err := range e.Errs {
+		if err != nil {
+			return fmt.Sprintf("dynamis: transaction canceled by operation %d: %s", i, err)
+		}
+	}
+	return "dynamis: transaction canceled: " + e.Err.Error()
+}
+
+// Unwrap returns the error of each operation that caused the cancellation,
+// followed by the error from DynamoDB.
+func (e *TransactionError) Unwrap() []error {
+	var errs []error
+	for _, err := range e.Errs {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if e.Err != nil {
+		errs = append(errs, e.Err)
+	}
+	return errs
+}
+
+// transactionErr decodes the cancellation reasons of a canceled transaction,
+// and returns any other error as it is.
+func transactionErr(err error) error {
+	canceled, ok := err.(*dynamodb.TransactionCanceledException)
+	if !ok {
+		return err
+	}
+	errs := make([]error, len(canceled.CancellationReasons))
+	for i, reason := range canceled.CancellationReasons {
+		switch code := aws.StringValue(reason.Code); code {
+		case "", "None":
+		case "ConditionalCheckFailed":
+			errs[i] = ErrConditionFailed
+		default:
+			msg := code
+			if m := aws.StringValue(reason.Message); m != "" {
+				msg += ": " + m
+			}
+			errs[i] = errors.New(msg)
+		}
+	}
+	return &TransactionError{errs, err}
+}
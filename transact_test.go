@@ -0,0 +1,178 @@
+package dynamis
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func newFakeTransactWriter(f func(*dynamodb.TransactWriteItemsInput) error) *TransactWriter {
+	tx := NewTransactWriter(nil)
+	tx.write = func(input *dynamodb.TransactWriteItemsInput) (*dynamodb.TransactWriteItemsOutput, error) {
+		return &dynamodb.TransactWriteItemsOutput{}, f(input)
+	}
+	return tx
+}
+
+func TestTransactWriter(t *testing.T) {
+	var items []*dynamodb.TransactWriteItem
+	tx := newFakeTransactWriter(func(input *dynamodb.TransactWriteItemsInput) error {
+		items = input.TransactItems
+		return nil
+	})
+	key := func(w ValueWriter) {
+		w.Str("id", "u1")
+	}
+	err := tx.
+		PutIf("users", Cond.AttributeNotExists("id"), func(w ValueWriter) {
+			w.Str("id", "u1")
+			w.Str("email", "r@example.com")
+		}).
+		Put("emails", func(w ValueWriter) {
+			w.Str("email", "r@example.com")
+		}).
+		UpdateIf("counts", Cond.Gt("n", 0), key, NewUpdateBuilder().Add("n", 1)).
+		Delete("old", key).
+		ConditionCheck("teams", key, Cond.AttributeExists("id")).
+		Run()
+	if err != nil {
+		t.Fatalf("Run() error %s", err)
+	}
+	if got, want := len(items), 5; got != want {
+		t.Fatalf("items got %d, want %d", got, want)
+	}
+	if p := items[0].Put; p == nil || *p.TableName != "users" || *p.ConditionExpression != "attribute_not_exists(#n0)" || Str(p.Item, "email") != "r@example.com" {
+		t.Errorf("PutIf() got %#v", items[0])
+	}
+	if p := items[1].Put; p == nil || p.ConditionExpression != nil || p.ExpressionAttributeNames != nil {
+		t.Errorf("Put() got %#v", items[1])
+	}
+	if u := items[2].Update; u == nil || *u.UpdateExpression != "ADD #n0 :v0" || *u.ConditionExpression != "#n0 > :v1" {
+		t.Errorf("UpdateIf() got %#v", items[2])
+	}
+	if d := items[3].Delete; d == nil || Str(d.Key, "id") != "u1" || d.ConditionExpression != nil {
+		t.Errorf("Delete() got %#v", items[3])
+	}
+	if c := items[4].ConditionCheck; c == nil || *c.ConditionExpression != "attribute_exists(#n0)" {
+		t.Errorf("ConditionCheck() got %#v", items[4])
+	}
+}
+
+func TestTransactWriterErrors(t *testing.T) {
+	called := false
+	newTx := func() *TransactWriter {
+		return newFakeTransactWriter(func(*dynamodb.TransactWriteItemsInput) error {
+			called = true
+			return nil
+		})
+	}
+	empty := func(w ValueWriter) { w.Str("id", "") }
+	key := func(w ValueWriter) { w.Str("id", "u1") }
+	tooMany := newTx()
+	for i := 0; i < maxTransactItems+1; i++ {
+		tooMany.Delete("t", key)
+	}
+	tests := []*TransactWriter{
+		newTx(),
+		tooMany,
+		newTx().Put("t", empty),
+		newTx().Delete("t", empty),
+		newTx().DeleteIf("t", Condition{}, key),
+		newTx().Update("t", key, NewUpdateBuilder()),
+		newTx().ConditionCheck("t", key, Cond.Eq("name", "")),
+	}
+	for i, tx := range tests {
+		if err := tx.Run(); err == nil {
+			t.Errorf("%d Run() want error", i)
+		}
+	}
+	if called {
+		t.Errorf("Run() with errors called DynamoDB")
+	}
+
+	// Errors building an operation can be matched.
+	if err := newTx().Put("t", empty).Run(); !errors.Is(err, ErrEmptyItem) {
+		t.Errorf("Run(empty put) got %v, want %v", err, ErrEmptyItem)
+	}
+	if err := newTx().Delete("t", empty).Run(); !errors.Is(err, ErrEmptyKey) {
+		t.Errorf("Run(empty delete) got %v, want %v", err, ErrEmptyKey)
+	}
+	var verr *ValueError
+	if err := newTx().ConditionCheck("t", key, Cond.Eq("name", "")).Run(); !errors.As(err, &verr) || verr.Err != ErrMissing {
+		t.Errorf("Run(empty value) got %#v, want *ValueError", err)
+	}
+}
+
+func TestTransactionError(t *testing.T) {
+	canceled := &dynamodb.TransactionCanceledException{
+		Message_: aws.String("Transaction cancelled"),
+		CancellationReasons: []*dynamodb.CancellationReason{
+			{Code: aws.String("None")},
+			{Code: aws.String("ConditionalCheckFailed"), Message: aws.String("The conditional request failed")},
+			{Code: aws.String("TransactionConflict"), Message: aws.String("Conflict")},
+		},
+	}
+	err := transactionErr(canceled)
+	terr, ok := err.(*TransactionError)
+	if !ok {
+		t.Fatalf("transactionErr() got %#v", err)
+	}
+	if len(terr.Errs) != 3 || terr.Errs[0] != nil || terr.Errs[1] != ErrConditionFailed {
+		t.Errorf("Errs got %#v", terr.Errs)
+	}
+	if got, want := terr.Errs[2].Error(), "TransactionConflict: Conflict"; got != want {
+		t.Errorf("Errs[2] got %q, want %q", got, want)
+	}
+	if got, want := terr.Error(), "dynamis: transaction canceled by operation 1: dynamis: condition failed"; got != want {
+		t.Errorf("Error() got %q, want %q", got, want)
+	}
+	if got := terr.Unwrap(); len(got) != 3 || got[0] != ErrConditionFailed || got[2] != canceled {
+		t.Errorf("Unwrap() got %#v", got)
+	}
+	var cerr *dynamodb.TransactionCanceledException
+	if !errors.Is(err, ErrConditionFailed) || !errors.As(err, &cerr) || cerr != canceled {
+		t.Errorf("transactionErr() doesn't wrap %#v", canceled)
+	}
+
+	other := errors.New("boom")
+	if got := transactionErr(other); got != other {
+		t.Errorf("transactionErr(other) got %#v", got)
+	}
+	if got := transactionErr(nil); got != nil {
+		t.Errorf("transactionErr(nil) got %#v", got)
+	}
+}
+
+func TestTableTransactWriter(t *testing.T) {
+	users, emails := newTable(), newTable()
+	if err := createStrTable(users); err != nil {
+		t.Fatalf("Failed initializing: %s", err)
+	}
+	if err := createStrTable(emails, "taken@example.com"); err != nil {
+		t.Fatalf("Failed initializing: %s", err)
+	}
+	signup := func(id, email string) error {
+		return NewTransactWriter(users.db).
+			PutIf(users.name, Cond.AttributeNotExists("str"), func(w ValueWriter) {
+				w.Str("str", id)
+				w.Str("email", email)
+			}).
+			PutIf(emails.name, Cond.AttributeNotExists("str"), func(w ValueWriter) {
+				w.Str("str", email)
+			}).
+			Run()
+	}
+	if err := signup("u1", "r@example.com"); err != nil {
+		t.Fatalf("Run() error %s", err)
+	}
+	err := signup("u2", "taken@example.com")
+	terr, ok := err.(*TransactionError)
+	if !ok || len(terr.Errs) != 2 || terr.Errs[0] != nil || terr.Errs[1] != ErrConditionFailed {
+		t.Errorf("Run(taken) got %#v", err)
+	}
+	if got, want := CheckTable(users.db, users.name).RowCount(), 1; got != want {
+		t.Errorf("RowCount() got %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,82 @@
+package dynamis
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// TransactGetter reads items from one or more tables as a consistent snapshot
+// with TransactGetItems. Add keys with Get, then call Run.
+type TransactGetter struct {
+	gets []transactGet
+
+	// get is replaced in tests.
+	get func(*dynamodb.TransactGetItemsInput) (*dynamodb.TransactGetItemsOutput, error)
+}
+
+type transactGet struct {
+	tableName string
+	key       func(ValueWriter)
+}
+
+// NewTransactGetter initializes an empty transaction.
+func NewTransactGetter(db *dynamodb.DynamoDB) *TransactGetter {
+	return &TransactGetter{get: db.TransactGetItems}
+}
+
+// Get adds a read of the item with the key written by the function. It
+// returns the getter, so calls can be chained.
+func (tx *TransactGetter) Get(tableName string, key func(ValueWriter)) *TransactGetter {
+	tx.gets = append(tx.gets, transactGet{tableName, key})
+	return tx
+}
+
+// items builds every read, checking DynamoDB's limits.
+func (tx *TransactGetter) items() ([]*dynamodb.TransactGetItem, error) {
+	if len(tx.gets) == 0 {
+		return nil, errors.New("dynamis: transaction is empty")
+	}
+	if len(tx.gets) > maxTransactItems {
+		return nil, fmt.Errorf("dynamis: transaction has %d operations, the limit is %d", len(tx.gets), maxTransactItems)
+	}
+	items := make([]*dynamodb.TransactGetItem, len(tx.gets))
+	for i, g := range tx.gets {
+		k, err := buildKey(g.key)
+		if err != nil {
+			return nil, fmt.Errorf("dynamis: transaction operation %d: %w", i, err)
+		}
+		items[i] = &dynamodb.TransactGetItem{Get: &dynamodb.Get{
+			TableName: aws.String(g.tableName),
+			Key:       k,
+		}}
+	}
+	return items, nil
+}
+
+// Run reads the items, returning a reader for each in the order they were
+// added. A missing item has an empty reader, which returns zero values. If
+// DynamoDB cancels the transaction, Run returns a *TransactionError.
+func (tx *TransactGetter) Run() ([]ValueReader, error) {
+	items, err := tx.items()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := tx.get(&dynamodb.TransactGetItemsInput{
+		TransactItems: items,
+	})
+	if err != nil {
+		return nil, transactionErr(err)
+	}
+	readers := make([]ValueReader, len(items))
+	for i := range readers {
+		var item map[string]*dynamodb.AttributeValue
+		if i < len(resp.Responses) && resp.Responses[i] != nil {
+			item = resp.Responses[i].Item
+		}
+		readers[i] = NewValueReader(item)
+	}
+	return readers, nil
+}
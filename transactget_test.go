@@ -0,0 +1,120 @@
+package dynamis
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestTransactGetter(t *testing.T) {
+	var input *dynamodb.TransactGetItemsInput
+	tx := NewTransactGetter(nil)
+	tx.get = func(in *dynamodb.TransactGetItemsInput) (*dynamodb.TransactGetItemsOutput, error) {
+		input = in
+		return &dynamodb.TransactGetItemsOutput{
+			Responses: []*dynamodb.ItemResponse{
+				{Item: map[string]*dynamodb.AttributeValue{"name": {S: aws.String("Ryan")}}},
+				{},
+				{Item: map[string]*dynamodb.AttributeValue{"n": {N: aws.String("3")}}},
+			},
+		}, nil
+	}
+	key := func(id string) func(ValueWriter) {
+		return func(w ValueWriter) {
+			w.Str("id", id)
+		}
+	}
+	readers, err := tx.
+		Get("users", key("u1")).
+		Get("users", key("u2")).
+		Get("counts", key("u1")).
+		Run()
+	if err != nil {
+		t.Fatalf("Run() error %s", err)
+	}
+	if got, want := len(input.TransactItems), 3; got != want {
+		t.Fatalf("TransactItems got %d, want %d", got, want)
+	}
+	if g := input.TransactItems[2].Get; *g.TableName != "counts" || Str(g.Key, "id") != "u1" {
+		t.Errorf("TransactItems[2] got %#v", g)
+	}
+	if got, want := len(readers), 3; got != want {
+		t.Fatalf("Run() got %d readers, want %d", got, want)
+	}
+	if got, want := readers[0].Str("name"), "Ryan"; got != want {
+		t.Errorf("readers[0] got %#v, want %#v", got, want)
+	}
+	// Missing items have empty readers.
+	if readers[1] == nil || readers[1].Has("name") || readers[1].Str("name") != "" {
+		t.Errorf("readers[1] got %#v", readers[1])
+	}
+	if got, want := readers[2].Int("n"), 3; got != want {
+		t.Errorf("readers[2] got %#v, want %#v", got, want)
+	}
+}
+
+func TestTransactGetterErrors(t *testing.T) {
+	failure := errors.New("boom")
+	newTx := func() *TransactGetter {
+		tx := NewTransactGetter(nil)
+		tx.get = func(*dynamodb.TransactGetItemsInput) (*dynamodb.TransactGetItemsOutput, error) {
+			return nil, failure
+		}
+		return tx
+	}
+	key := func(w ValueWriter) { w.Str("id", "u1") }
+	tooMany := newTx()
+	for i := 0; i < maxTransactItems+1; i++ {
+		tooMany.Get("t", key)
+	}
+	tests := []struct {
+		tx  *TransactGetter
+		err error
+	}{
+		{tx: newTx()},
+		{tx: tooMany},
+		{tx: newTx().Get("t", func(w ValueWriter) { w.Str("id", "") }), err: ErrEmptyKey},
+		{tx: newTx().Get("t", key), err: failure},
+	}
+	for i, test := range tests {
+		readers, err := test.tx.Run()
+		if err == nil || readers != nil {
+			t.Errorf("%d Run() got (%#v, %v), want error", i, readers, err)
+		}
+		if test.err != nil && !errors.Is(err, test.err) {
+			t.Errorf("%d Run() got %v, want %v", i, err, test.err)
+		}
+	}
+}
+
+func TestTableTransactGetter(t *testing.T) {
+	a, b := newTable(), newTable()
+	if err := createStrTable(a, "a1"); err != nil {
+		t.Fatalf("Failed initializing: %s", err)
+	}
+	if err := createStrTable(b, "b1"); err != nil {
+		t.Fatalf("Failed initializing: %s", err)
+	}
+	key := func(s string) func(ValueWriter) {
+		return func(w ValueWriter) {
+			w.Str("str", s)
+		}
+	}
+	readers, err := NewTransactGetter(a.db).
+		Get(b.name, key("b1")).
+		Get(a.name, key("missing")).
+		Get(a.name, key("a1")).
+		Run()
+	if err != nil {
+		t.Fatalf("Run() error %s", err)
+	}
+	var got []string
+	for _, r := range readers {
+		got = append(got, r.Str("str"))
+	}
+	if len(got) != 3 || got[0] != "b1" || got[1] != "" || got[2] != "a1" {
+		t.Errorf("Run() got %#v", got)
+	}
+}
@@ -0,0 +1,246 @@
+package dynamis
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// The clauses of an update expression, in the order they are written.
+var updateClauses = []string{"SET", "REMOVE", "ADD", "DELETE"}
+
+// updateAction is one action of an update expression on the attribute at
+// path. Its build function returns the clause it belongs in and the action.
+// An empty clause means there is nothing to do.
+type updateAction struct {
+	path  string
+	build func(b *exprBuilder) (clause, expr string, err error)
+}
+
+// UpdateBuilder builds an update expression, such as the UpdateExpression of
+// an UpdateItem. Its methods add actions and return the builder, so they can
+// be chained:
+//
+//	u := NewUpdateBuilder().Set("name", name).Add("logins", 1).Remove("token")
+//
+// Attribute names are document paths and values are encoded as in Cond. Like
+// SetStr, Set doesn't store empty values; it removes the attribute instead.
+// DynamoDB allows one action per attribute, so an update whose paths are the
+// same or nested in one another, such as "a" and "a.b", fails to build.
+type UpdateBuilder struct {
+	actions []updateAction
+}
+
+// NewUpdateBuilder initializes an empty update.
+func NewUpdateBuilder() *UpdateBuilder {
+	return &UpdateBuilder{}
+}
+
+func (u *UpdateBuilder) add(path string, build func(b *exprBuilder) (string, string, error)) *UpdateBuilder {
+	u.actions = append(u.actions, updateAction{path, build})
+	return u
+}
+
+// Set sets the attribute at path to v. If v is empty, such as "", nil or an
+// empty slice, the attribute is removed instead.
+func (u *UpdateBuilder) Set(path string, v interface{}) *UpdateBuilder {
+	return u.add(path, func(b *exprBuilder) (string, string, error) {
+		name, err := b.name(path)
+		if err != nil {
+			return "", "", err
+		}
+		val, ok, err := b.encode(path, v, false)
+		if err != nil {
+			return "", "", err
+		}
+		if !ok {
+			return "REMOVE", name, nil
+		}
+		return "SET", name + " = " + val, nil
+	})
+}
+
+// SetIfNotExists sets the attribute at path to v only if the item doesn't
+// have it already. If v is empty, nothing is done.
+func (u *UpdateBuilder) SetIfNotExists(path string, v interface{}) *UpdateBuilder {
+	return u.add(path, func(b *exprBuilder) (string, string, error) {
+		name, err := b.name(path)
+		if err != nil {
+			return "", "", err
+		}
+		val, ok, err := b.encode(path, v, false)
+		if err != nil || !ok {
+			return "", "", err
+		}
+		return "SET", name + " = if_not_exists(" + name + ", " + val + ")", nil
+	})
+}
+
+// Append adds values to the end of the list at path, creating the list if it
+// doesn't exist. Empty values are skipped, as by ListWriter, and if every
+// value is empty nothing is done.
+func (u *UpdateBuilder) Append(path string, vs ...interface{}) *UpdateBuilder {
+	return u.add(path, func(b *exprBuilder) (string, string, error) {
+		name, err := b.name(path)
+		if err != nil {
+			return "", "", err
+		}
+		val, ok, err := b.encode(path, vs, false)
+		if err != nil || !ok {
+			return "", "", err
+		}
+		empty := b.raw(&dynamodb.AttributeValue{L: []*dynamodb.AttributeValue{}})
+		return "SET", name + " = list_append(if_not_exists(" + name + ", " + empty + "), " + val + ")", nil
+	})
+}
+
+// Remove removes the attributes at the paths.
+func (u *UpdateBuilder) Remove(paths ...string) *UpdateBuilder {
+	for _, path := range paths {
+		path := path
+		u.add(path, func(b *exprBuilder) (string, string, error) {
+			name, err := b.name(path)
+			return "REMOVE", name, err
+		})
+	}
+	return u
+}
+
+// Add adds the number v to the number at path, or the elements of the slice v
+// to the set at path. A missing attribute starts from zero or an empty set. If
+// v is an empty slice, nothing is done.
+func (u *UpdateBuilder) Add(path string, v interface{}) *UpdateBuilder {
+	return u.setAction("ADD", path, v)
+}
+
+// Delete removes the elements of the slice v from the set at path. If v is
+// empty, nothing is done.
+func (u *UpdateBuilder) Delete(path string, v interface{}) *UpdateBuilder {
+	return u.setAction("DELETE", path, v)
+}
+
+// setAction adds an ADD or DELETE action, which encode slices as sets.
+func (u *UpdateBuilder) setAction(clause, path string, v interface{}) *UpdateBuilder {
+	return u.add(path, func(b *exprBuilder) (string, string, error) {
+		name, err := b.name(path)
+		if err != nil {
+			return "", "", err
+		}
+		val, ok, err := b.encode(path, v, true)
+		if err != nil || !ok {
+			return "", "", err
+		}
+		return clause, name + " " + val, nil
+	})
+}
+
+// expr builds the update expression with a shared builder.
+func (u *UpdateBuilder) expr(b *exprBuilder) (string, error) {
+	if u == nil {
+		return "", errors.New("dynamis: update is nil")
+	}
+	actions := make(map[string][]string)
+	var paths [][]pathElem
+	for _, a := range u.actions {
+		clause, expr, err := a.build(b)
+		if err != nil {
+			return "", err
+		}
+		if clause == "" {
+			continue
+		}
+		elems, err := parsePath(a.path)
+		if err != nil {
+			return "", err
+		}
+		for _, p := range paths {
+			if pathsOverlap(p, elems) {
+				return "", fmt.Errorf("dynamis: update paths %q and %q overlap", formatPath(p), a.path)
+			}
+		}
+		paths = append(paths, elems)
+		actions[clause] = append(actions[clause], expr)
+	}
+	var clauses []string
+	for _, clause := range updateClauses {
+		if len(actions[clause]) > 0 {
+			clauses = append(clauses, clause+" "+strings.Join(actions[clause], ", "))
+		}
+	}
+	if len(clauses) == 0 {
+		return "", errors.New("dynamis: update has no actions")
+	}
+	return strings.Join(clauses, " "), nil
+}
+
+// Build returns the update expression along with the maps to use as its
+// ExpressionAttributeNames and ExpressionAttributeValues. A map is nil if it
+// would be empty. Build returns an error if the builder is nil, if no action
+// remains once empty values are skipped, or if two actions overlap.
+func (u *UpdateBuilder) Build() (string, map[string]*string, map[string]*dynamodb.AttributeValue, error) {
+	b := newExprBuilder(DefaultCodecs)
+	expr, err := u.expr(b)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return expr, b.attrNames(), b.attrValues(), nil
+}
+
+// Update applies the update to the item with the key written by the function,
+// creating the item if it doesn't exist. It returns a reader of the item after
+// the update. The reader is never nil, so it can be used even when there is an
+// error.
+func (t Table) Update(key func(ValueWriter), u *UpdateBuilder) (ValueReader, error) {
+	return t.update(nil, key, u)
+}
+
+// UpdateIf is like Update, but applies the update only if the existing item
+// matches the condition. It returns ErrConditionFailed if it does not.
+func (t Table) UpdateIf(cond Condition, key func(ValueWriter), u *UpdateBuilder) (ValueReader, error) {
+	return t.update(&cond, key, u)
+}
+
+func (t Table) update(cond *Condition, key func(ValueWriter), u *UpdateBuilder) (ValueReader, error) {
+	input, err := t.updateInput(cond, key, u)
+	if err != nil {
+		return NewValueReader(nil), err
+	}
+	resp, err := t.db.UpdateItem(input)
+	if err != nil {
+		return NewValueReader(nil), conditionErr(err)
+	}
+	return NewValueReader(resp.Attributes), nil
+}
+
+// updateInput builds the input to update an item. The update and condition
+// share placeholders.
+func (t Table) updateInput(cond *Condition, key func(ValueWriter), u *UpdateBuilder) (*dynamodb.UpdateItemInput, error) {
+	k, err := buildKey(key)
+	if err != nil {
+		return nil, err
+	}
+	b := newExprBuilder(DefaultCodecs)
+	expr, err := u.expr(b)
+	if err != nil {
+		return nil, err
+	}
+	input := &dynamodb.UpdateItemInput{
+		TableName:        aws.String(t.tableName),
+		Key:              k,
+		UpdateExpression: aws.String(expr),
+		ReturnValues:     aws.String(dynamodb.ReturnValueAllNew),
+	}
+	if cond != nil {
+		condExpr, err := cond.expr(b)
+		if err != nil {
+			return nil, err
+		}
+		input.ConditionExpression = aws.String(condExpr)
+	}
+	input.ExpressionAttributeNames = b.attrNames()
+	input.ExpressionAttributeValues = b.attrValues()
+	return input, nil
+}
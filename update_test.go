@@ -0,0 +1,203 @@
+package dynamis
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestUpdateBuilder(t *testing.T) {
+	tests := []struct {
+		update *UpdateBuilder
+		expr   string
+		names  map[string]*string
+		values map[string]*dynamodb.AttributeValue
+	}{
+		{
+			update: NewUpdateBuilder().Set("name", "Ryan"),
+			expr:   "SET #n0 = :v0",
+			names:  map[string]*string{"#n0": aws.String("name")},
+			values: map[string]*dynamodb.AttributeValue{
+				":v0": {S: aws.String("Ryan")},
+			},
+		},
+		{
+			// Empty values are removed.
+			update: NewUpdateBuilder().Set("name", "").Set("age", nil).Set("tags", []string{}),
+			expr:   "REMOVE #n0, #n1, #n2",
+		},
+		{
+			update: NewUpdateBuilder().SetIfNotExists("created", 5).SetIfNotExists("name", ""),
+			expr:   "SET #n0 = if_not_exists(#n0, :v0)",
+		},
+		{
+			update: NewUpdateBuilder().Append("events", "a", "", "b"),
+			expr:   "SET #n0 = list_append(if_not_exists(#n0, :v1), :v0)",
+			values: map[string]*dynamodb.AttributeValue{
+				":v0": {L: []*dynamodb.AttributeValue{
+					{S: aws.String("a")},
+					{S: aws.String("b")},
+				}},
+				":v1": {L: []*dynamodb.AttributeValue{}},
+			},
+		},
+		{
+			update: NewUpdateBuilder().Append("events", "").Set("n", 1),
+			expr:   "SET #n1 = :v0",
+		},
+		{
+			update: NewUpdateBuilder().Remove("a", "b.c", "d[1]"),
+			expr:   "REMOVE #n0, #n1.#n2, #n3[1]",
+		},
+		{
+			update: NewUpdateBuilder().Add("logins", 1).Add("tags", []string{"b", "a"}).Add("none", []int{}),
+			expr:   "ADD #n0 :v0, #n1 :v1",
+			values: map[string]*dynamodb.AttributeValue{
+				":v0": {N: aws.String("1")},
+				":v1": {SS: aws.StringSlice([]string{"a", "b"})},
+			},
+		},
+		{
+			update: NewUpdateBuilder().Delete("lucky", []int{7}),
+			expr:   "DELETE #n0 :v0",
+			values: map[string]*dynamodb.AttributeValue{
+				":v0": {NS: aws.StringSlice([]string{"7"})},
+			},
+		},
+		{
+			// Clauses are grouped in a fixed order.
+			update: NewUpdateBuilder().Delete("d", []string{"x"}).Add("a", 1).Remove("r").Set("s", "v").Set("e", ""),
+			expr:   "SET #n3 = :v2 REMOVE #n2, #n4 ADD #n1 :v1 DELETE #n0 :v0",
+		},
+		{
+			// Sibling paths don't overlap, and skipped actions don't count.
+			update: NewUpdateBuilder().Set("a.b", 1).Set("a.c", 2).Remove("l[0]", "l[1]").SetIfNotExists("a", ""),
+			expr:   "SET #n0.#n1 = :v0, #n0.#n2 = :v1 REMOVE #n3[0], #n3[1]",
+		},
+	}
+	for i, test := range tests {
+		expr, names, values, err := test.update.Build()
+		if err != nil {
+			t.Errorf("%d Build() error %s", i, err)
+			continue
+		}
+		if expr != test.expr {
+			t.Errorf("%d Build() got %q, want %q", i, expr, test.expr)
+		}
+		if test.names != nil && !reflect.DeepEqual(names, test.names) {
+			t.Errorf("%d Build() names got %#v, want %#v", i, names, test.names)
+		}
+		if test.values != nil && !reflect.DeepEqual(values, test.values) {
+			t.Errorf("%d Build() values got %#v, want %#v", i, values, test.values)
+		}
+	}
+}
+
+func TestUpdateBuilderErrors(t *testing.T) {
+	tests := []*UpdateBuilder{
+		NewUpdateBuilder(),
+		NewUpdateBuilder().SetIfNotExists("name", ""),
+		NewUpdateBuilder().Set("", "v"),
+		NewUpdateBuilder().Set("c", make(chan int)),
+		NewUpdateBuilder().Add("b", []bool{true}),
+		NewUpdateBuilder().Remove("a[x]"),
+		NewUpdateBuilder().Set("a", "").Remove("a"),
+		NewUpdateBuilder().Set("a", 1).Set("a", 2),
+		NewUpdateBuilder().Set("a", 1).Add("a.b", 1),
+		NewUpdateBuilder().Remove("l[0].x").Append("l", "v"),
+		nil,
+	}
+	for i, u := range tests {
+		if _, _, _, err := u.Build(); err == nil {
+			t.Errorf("%d Build() want error", i)
+		}
+	}
+}
+
+func TestUpdateInput(t *testing.T) {
+	table := Table{tableName: "users"}
+	key := func(w ValueWriter) {
+		w.Str("id", "u1")
+	}
+	cond := Cond.Eq("version", 1)
+	input, err := table.updateInput(&cond, key, NewUpdateBuilder().Set("version", 2))
+	if err != nil {
+		t.Fatalf("updateInput() error %s", err)
+	}
+	// The update and condition share placeholders.
+	if got, want := *input.UpdateExpression, "SET #n0 = :v0"; got != want {
+		t.Errorf("UpdateExpression got %q, want %q", got, want)
+	}
+	if got, want := *input.ConditionExpression, "#n0 = :v1"; got != want {
+		t.Errorf("ConditionExpression got %q, want %q", got, want)
+	}
+	if got, want := len(input.ExpressionAttributeValues), 2; got != want {
+		t.Errorf("ExpressionAttributeValues len got %d, want %d", got, want)
+	}
+	if _, err := table.updateInput(nil, func(ValueWriter) {}, NewUpdateBuilder().Set("a", 1)); err != ErrEmptyKey {
+		t.Errorf("updateInput(empty key) got %v, want %v", err, ErrEmptyKey)
+	}
+	if _, err := table.updateInput(nil, key, nil); err == nil {
+		t.Errorf("updateInput(nil update) want error")
+	}
+}
+
+func TestTableUpdate(t *testing.T) {
+	tbl := newTable()
+	if err := createStrTable(tbl); err != nil {
+		t.Fatalf("Failed initializing: %s", err)
+	}
+	table := CheckTable(tbl.db, tbl.name)
+	key := func(w ValueWriter) {
+		w.Str("str", "k1")
+	}
+
+	r, err := table.Update(key, NewUpdateBuilder().
+		Set("name", "Ryan").
+		SetIfNotExists("created", 1).
+		Add("logins", 1).
+		Add("tags", []string{"a", "b"}).
+		Append("events", "signup"))
+	if err != nil {
+		t.Fatalf("Update() error %s", err)
+	}
+	if got, want := r.Str("name"), "Ryan"; got != want {
+		t.Errorf("Update() name got %#v, want %#v", got, want)
+	}
+
+	r, err = table.Update(key, NewUpdateBuilder().
+		Set("name", "").
+		SetIfNotExists("created", 2).
+		Add("logins", 1).
+		Delete("tags", []string{"a"}).
+		Append("events", "login"))
+	if err != nil {
+		t.Fatalf("Update() error %s", err)
+	}
+	if r.Has("name") {
+		t.Errorf("Update() got removed name %#v", r.Str("name"))
+	}
+	if got, want := r.Int("created"), 1; got != want {
+		t.Errorf("Update() created got %d, want %d", got, want)
+	}
+	if got, want := r.Int("logins"), 2; got != want {
+		t.Errorf("Update() logins got %d, want %d", got, want)
+	}
+	if got, want := r.StrSet("tags"), []string{"b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Update() tags got %#v, want %#v", got, want)
+	}
+	if got, want := r.Len("events"), 2; got != want {
+		t.Errorf("Update() events len got %d, want %d", got, want)
+	}
+
+	_, err = table.UpdateIf(Cond.Eq("logins", 1), key, NewUpdateBuilder().Add("logins", 1))
+	if err != ErrConditionFailed {
+		t.Errorf("UpdateIf() got %v, want %v", err, ErrConditionFailed)
+	}
+	r, err = table.UpdateIf(Cond.Eq("logins", 2), key, NewUpdateBuilder().Add("logins", 1))
+	if err != nil || r.Int("logins") != 3 {
+		t.Errorf("UpdateIf() got (%d, %v), want 3", r.Int("logins"), err)
+	}
+}
@@ -1,6 +1,10 @@
 package dynamis
 
 import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
 	"strconv"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -10,33 +14,286 @@ import (
 // Str returns a string from a DynamoDB attribute value. If anything goes wrong
 // reading the value, an empty string is returned.
 func Str(item map[string]*dynamodb.AttributeValue, key string) string {
-	if item == nil {
-		return ""
-	}
-	if val, ok := item[key]; ok {
-		if val.S == nil {
-			return ""
-		}
-		return *val.S
-	}
-	return ""
+	s, _ := strE(item, key)
+	return s
 }
 
 // Int returns an int from a DynamoDB attribute value. If anything goes wrong
 // reading or parsing the value, 0 is returned.
 func Int(item map[string]*dynamodb.AttributeValue, key string) int {
-	if item == nil {
-		return 0
+	i, _ := intE(item, key)
+	return i
+}
+
+// Int64 returns an int64 from a DynamoDB attribute value. If anything goes
+// wrong reading or parsing the value, 0 is returned.
+func Int64(item map[string]*dynamodb.AttributeValue, key string) int64 {
+	i, _ := int64E(item, key)
+	return i
+}
+
+// Uint64 returns a uint64 from a DynamoDB attribute value. If anything goes
+// wrong reading or parsing the value, including a negative number, 0 is
+// returned.
+func Uint64(item map[string]*dynamodb.AttributeValue, key string) uint64 {
+	i, _ := uint64E(item, key)
+	return i
+}
+
+// Float64 returns a float64 from a DynamoDB attribute value. If anything goes
+// wrong reading or parsing the value, 0 is returned.
+func Float64(item map[string]*dynamodb.AttributeValue, key string) float64 {
+	f, _ := float64E(item, key)
+	return f
+}
+
+// Bool returns a bool from a DynamoDB attribute value. If anything goes wrong
+// reading the value, false is returned.
+func Bool(item map[string]*dynamodb.AttributeValue, key string) bool {
+	b, _ := boolE(item, key)
+	return b
+}
+
+// Bytes returns a byte slice from a DynamoDB binary attribute value. If
+// anything goes wrong reading the value, nil is returned.
+func Bytes(item map[string]*dynamodb.AttributeValue, key string) []byte {
+	b, _ := bytesE(item, key)
+	return b
+}
+
+// StrSet returns the members of a DynamoDB string set, sorted and without
+// duplicates. If anything goes wrong reading the value, nil is returned.
+func StrSet(item map[string]*dynamodb.AttributeValue, key string) []string {
+	ss, _ := strSetE(item, key)
+	return ss
+}
+
+// IntSet returns the members of a DynamoDB number set, sorted and without
+// duplicates. Members that cannot be parsed as an int are ignored. If
+// anything goes wrong reading the value, nil is returned.
+func IntSet(item map[string]*dynamodb.AttributeValue, key string) []int {
+	ns, _ := intSetE(item, key)
+	return ns
+}
+
+// BytesSet returns the members of a DynamoDB binary set, sorted and without
+// duplicates. If anything goes wrong reading the value, nil is returned.
+func BytesSet(item map[string]*dynamodb.AttributeValue, key string) [][]byte {
+	bs, _ := bytesSetE(item, key)
+	return bs
+}
+
+// Has returns true if the item contains key, whatever its value. An explicit
+// NULL value counts.
+func Has(item map[string]*dynamodb.AttributeValue, key string) bool {
+	val, ok := item[key]
+	return ok && val != nil
+}
+
+// IsNull returns true if the value at key is an explicit NULL.
+func IsNull(item map[string]*dynamodb.AttributeValue, key string) bool {
+	val, ok := item[key]
+	return ok && val != nil && val.NULL != nil && *val.NULL
+}
+
+// Map returns the nested item stored in a DynamoDB map attribute. If anything
+// goes wrong reading the value, nil is returned.
+func Map(item map[string]*dynamodb.AttributeValue, key string) map[string]*dynamodb.AttributeValue {
+	m, _ := mapE(item, key)
+	return m
+}
+
+// List returns the elements of a DynamoDB list attribute. If anything goes
+// wrong reading the value, nil is returned.
+func List(item map[string]*dynamodb.AttributeValue, key string) []*dynamodb.AttributeValue {
+	l, _ := listE(item, key)
+	return l
+}
+
+// The following functions read a value like their exported counterparts, but
+// return ErrMissing, ErrNull, ErrType or a parse error when the value can't be
+// read. Callers wrap the error in a ValueError with the full path of the key.
+
+func attrE(item map[string]*dynamodb.AttributeValue, key string) (*dynamodb.AttributeValue, error) {
+	if !Has(item, key) {
+		return nil, ErrMissing
+	}
+	if IsNull(item, key) {
+		return nil, ErrNull
+	}
+	return item[key], nil
+}
+
+func strE(item map[string]*dynamodb.AttributeValue, key string) (string, error) {
+	val, err := attrE(item, key)
+	if err != nil {
+		return "", err
+	}
+	if val.S == nil {
+		return "", ErrType
+	}
+	return *val.S, nil
+}
+
+func numE(item map[string]*dynamodb.AttributeValue, key string) (string, error) {
+	val, err := attrE(item, key)
+	if err != nil {
+		return "", err
+	}
+	if val.N == nil {
+		return "", ErrType
+	}
+	return *val.N, nil
+}
+
+func intE(item map[string]*dynamodb.AttributeValue, key string) (int, error) {
+	n, err := numE(item, key)
+	if err != nil {
+		return 0, err
 	}
-	if val, ok := item[key]; ok {
-		if val.N == nil {
-			return 0
+	i, err := strconv.Atoi(n)
+	if err != nil {
+		return 0, err
+	}
+	return i, nil
+}
+
+func int64E(item map[string]*dynamodb.AttributeValue, key string) (int64, error) {
+	n, err := numE(item, key)
+	if err != nil {
+		return 0, err
+	}
+	i, err := strconv.ParseInt(n, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return i, nil
+}
+
+func uint64E(item map[string]*dynamodb.AttributeValue, key string) (uint64, error) {
+	n, err := numE(item, key)
+	if err != nil {
+		return 0, err
+	}
+	i, err := strconv.ParseUint(n, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return i, nil
+}
+
+func float64E(item map[string]*dynamodb.AttributeValue, key string) (float64, error) {
+	n, err := numE(item, key)
+	if err != nil {
+		return 0, err
+	}
+	f, err := strconv.ParseFloat(n, 64)
+	if err != nil {
+		return 0, err
+	}
+	return f, nil
+}
+
+func boolE(item map[string]*dynamodb.AttributeValue, key string) (bool, error) {
+	val, err := attrE(item, key)
+	if err != nil {
+		return false, err
+	}
+	if val.BOOL == nil {
+		return false, ErrType
+	}
+	return *val.BOOL, nil
+}
+
+func bytesE(item map[string]*dynamodb.AttributeValue, key string) ([]byte, error) {
+	val, err := attrE(item, key)
+	if err != nil {
+		return nil, err
+	}
+	if val.B == nil {
+		return nil, ErrType
+	}
+	return val.B, nil
+}
+
+func strSetE(item map[string]*dynamodb.AttributeValue, key string) ([]string, error) {
+	val, err := attrE(item, key)
+	if err != nil {
+		return nil, err
+	}
+	if val.SS == nil {
+		return nil, ErrType
+	}
+	return uniqueStrs(aws.StringValueSlice(val.SS)), nil
+}
+
+// intSetE returns every member that parses, along with the first parse error.
+func intSetE(item map[string]*dynamodb.AttributeValue, key string) ([]int, error) {
+	val, err := attrE(item, key)
+	if err != nil {
+		return nil, err
+	}
+	if val.NS == nil {
+		return nil, ErrType
+	}
+	var ints []int
+	var perr error
+	for _, n := range val.NS {
+		if n == nil {
+			continue
 		}
-		if i, err := strconv.Atoi(*val.N); err == nil {
-			return i
+		i, err := strconv.Atoi(*n)
+		if err != nil {
+			if perr == nil {
+				perr = err
+			}
+			continue
+		}
+		ints = append(ints, i)
+	}
+	return uniqueInts(ints), perr
+}
+
+func bytesSetE(item map[string]*dynamodb.AttributeValue, key string) ([][]byte, error) {
+	val, err := attrE(item, key)
+	if err != nil {
+		return nil, err
+	}
+	if val.BS == nil {
+		return nil, ErrType
+	}
+	return uniqueBytes(val.BS), nil
+}
+
+func mapE(item map[string]*dynamodb.AttributeValue, key string) (map[string]*dynamodb.AttributeValue, error) {
+	val, err := attrE(item, key)
+	if err != nil {
+		return nil, err
+	}
+	if val.M == nil {
+		return nil, ErrType
+	}
+	return val.M, nil
+}
+
+func listE(item map[string]*dynamodb.AttributeValue, key string) ([]*dynamodb.AttributeValue, error) {
+	val, err := attrE(item, key)
+	if err != nil {
+		return nil, err
+	}
+	if val.L == nil {
+		return nil, ErrType
+	}
+	return val.L, nil
+}
+
+// SetNull stores an explicit NULL attribute.
+func SetNull(item map[string]*dynamodb.AttributeValue, key string) {
+	if key != "" {
+		item[key] = &dynamodb.AttributeValue{
+			NULL: aws.Bool(true),
 		}
 	}
-	return 0
 }
 
 // SetStr stores a string attribute. If the string is empty, it is not stored.
@@ -50,9 +307,199 @@ func SetStr(item map[string]*dynamodb.AttributeValue, key string, val string) {
 
 // SetInt stores an int attribute.
 func SetInt(item map[string]*dynamodb.AttributeValue, key string, val int) {
+	SetInt64(item, key, int64(val))
+}
+
+// SetInt64 stores an int64 attribute.
+func SetInt64(item map[string]*dynamodb.AttributeValue, key string, val int64) {
+	if key != "" {
+		item[key] = &dynamodb.AttributeValue{
+			N: aws.String(strconv.FormatInt(val, 10)),
+		}
+	}
+}
+
+// SetUint64 stores a uint64 attribute.
+func SetUint64(item map[string]*dynamodb.AttributeValue, key string, val uint64) {
+	if key != "" {
+		item[key] = &dynamodb.AttributeValue{
+			N: aws.String(strconv.FormatUint(val, 10)),
+		}
+	}
+}
+
+// SetFloat64 stores a float64 attribute. Values too small for DynamoDB are
+// stored as 0. NaN, infinite and values too large for DynamoDB can't be
+// stored, so they are skipped; Marshal and Encode return an error instead.
+func SetFloat64(item map[string]*dynamodb.AttributeValue, key string, val float64) {
+	n, err := formatFloat(val)
+	if key != "" && err == nil {
+		item[key] = &dynamodb.AttributeValue{
+			N: aws.String(n),
+		}
+	}
+}
+
+// formatFloat formats a float64 as a DynamoDB number. Values too small for
+// DynamoDB round to 0, and other values it can't store are an error.
+func formatFloat(val float64) (string, error) {
+	if math.IsNaN(val) || math.IsInf(val, 0) {
+		return "", fmt.Errorf("dynamis: number %v can't be stored", val)
+	}
+	n := strconv.FormatFloat(val, 'g', -1, 64)
+	if err := ValidateNumber(n); err != nil {
+		if math.Abs(val) < 1 {
+			return "0", nil
+		}
+		return "", err
+	}
+	return n, nil
+}
+
+// SetBool stores a bool attribute.
+func SetBool(item map[string]*dynamodb.AttributeValue, key string, val bool) {
 	if key != "" {
 		item[key] = &dynamodb.AttributeValue{
-			N: aws.String(strconv.FormatInt(int64(val), 10)),
+			BOOL: aws.Bool(val),
 		}
 	}
 }
+
+// SetBytes stores a binary attribute. If the slice is empty, it is not
+// stored.
+func SetBytes(item map[string]*dynamodb.AttributeValue, key string, val []byte) {
+	if key != "" && len(val) > 0 {
+		item[key] = &dynamodb.AttributeValue{
+			B: val,
+		}
+	}
+}
+
+// SetStrSet stores a string set attribute. Empty strings and duplicates are
+// removed, and if nothing remains the set is not stored.
+func SetStrSet(item map[string]*dynamodb.AttributeValue, key string, val []string) {
+	set := uniqueStrs(val)
+	if key != "" && len(set) > 0 {
+		item[key] = &dynamodb.AttributeValue{
+			SS: aws.StringSlice(set),
+		}
+	}
+}
+
+// SetIntSet stores a number set attribute. Duplicates are removed, and if the
+// set is empty it is not stored.
+func SetIntSet(item map[string]*dynamodb.AttributeValue, key string, val []int) {
+	set := uniqueInts(val)
+	if key != "" && len(set) > 0 {
+		ns := make([]*string, len(set))
+		for i, n := range set {
+			ns[i] = aws.String(strconv.Itoa(n))
+		}
+		item[key] = &dynamodb.AttributeValue{
+			NS: ns,
+		}
+	}
+}
+
+// SetBytesSet stores a binary set attribute. Empty values and duplicates are
+// removed, and if nothing remains the set is not stored.
+func SetBytesSet(item map[string]*dynamodb.AttributeValue, key string, val [][]byte) {
+	set := uniqueBytes(val)
+	if key != "" && len(set) > 0 {
+		item[key] = &dynamodb.AttributeValue{
+			BS: set,
+		}
+	}
+}
+
+// SetMap stores a nested item as a map attribute. If the nested item is empty,
+// it is not stored.
+func SetMap(item map[string]*dynamodb.AttributeValue, key string, val map[string]*dynamodb.AttributeValue) {
+	if key != "" && len(val) > 0 {
+		item[key] = &dynamodb.AttributeValue{
+			M: val,
+		}
+	}
+}
+
+// SetList stores a list attribute. Nil elements are removed, and if nothing
+// remains the list is not stored.
+func SetList(item map[string]*dynamodb.AttributeValue, key string, val []*dynamodb.AttributeValue) {
+	var list []*dynamodb.AttributeValue
+	for _, v := range val {
+		if v != nil {
+			list = append(list, v)
+		}
+	}
+	if key != "" && len(list) > 0 {
+		item[key] = &dynamodb.AttributeValue{
+			L: list,
+		}
+	}
+}
+
+// uniqueStrs returns the non-empty strings in sorted order without
+// duplicates.
+func uniqueStrs(vals []string) []string {
+	var set []string
+	for _, v := range vals {
+		if v != "" {
+			set = append(set, v)
+		}
+	}
+	sort.Strings(set)
+	out := set[:0]
+	for i, v := range set {
+		if i == 0 || v != set[i-1] {
+			out = append(out, v)
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// uniqueInts returns the ints in sorted order without duplicates.
+func uniqueInts(vals []int) []int {
+	set := append([]int(nil), vals...)
+	sort.Ints(set)
+	out := set[:0]
+	for i, v := range set {
+		if i == 0 || v != set[i-1] {
+			out = append(out, v)
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// uniqueBytes returns the non-empty byte slices in sorted order without
+// duplicates.
+func uniqueBytes(vals [][]byte) [][]byte {
+	var set [][]byte
+	for _, v := range vals {
+		if len(v) > 0 {
+			set = append(set, v)
+		}
+	}
+	sort.Sort(byteSlices(set))
+	out := set[:0]
+	for i, v := range set {
+		if i == 0 || !bytes.Equal(v, set[i-1]) {
+			out = append(out, v)
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+type byteSlices [][]byte
+
+func (b byteSlices) Len() int           { return len(b) }
+func (b byteSlices) Less(i, j int) bool { return bytes.Compare(b[i], b[j]) < 0 }
+func (b byteSlices) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
@@ -1,6 +1,7 @@
 package dynamis
 
 import (
+	"math"
 	"reflect"
 	"testing"
 
@@ -171,3 +172,722 @@ func TestSetInt(t *testing.T) {
 		}
 	}
 }
+
+func TestInt64(t *testing.T) {
+	tests := []struct {
+		item map[string]*dynamodb.AttributeValue
+		key  string
+		want int64
+	}{
+		{
+			// Item is nil, returns the zero value.
+			item: nil,
+			key:  "k",
+			want: 0,
+		},
+		{
+			// Key does not exist, returns the zero value.
+			item: map[string]*dynamodb.AttributeValue{},
+			key:  "k",
+			want: 0,
+		},
+		{
+			// Key exists with no value, returns the zero value.
+			item: map[string]*dynamodb.AttributeValue{"k": {}},
+			key:  "k",
+			want: 0,
+		},
+		{
+			// Key exists, fails to parse. Returns the zero value.
+			item: map[string]*dynamodb.AttributeValue{"k": {N: aws.String("1.5")}},
+			key:  "k",
+			want: 0,
+		},
+		{
+			// Key exists, is a large number.
+			item: map[string]*dynamodb.AttributeValue{"k": {N: aws.String("9223372036854775807")}},
+			key:  "k",
+			want: math.MaxInt64,
+		},
+		{
+			// Key exists, is negative number.
+			item: map[string]*dynamodb.AttributeValue{"k": {N: aws.String("-1")}},
+			key:  "k",
+			want: -1,
+		},
+	}
+	for i, test := range tests {
+		got := Int64(test.item, test.key)
+		if got != test.want {
+			t.Errorf("%d Int64() got %#v, want %#v", i, got, test.want)
+		}
+	}
+}
+
+func TestUint64(t *testing.T) {
+	tests := []struct {
+		item map[string]*dynamodb.AttributeValue
+		key  string
+		want uint64
+	}{
+		{
+			// Item is nil, returns the zero value.
+			item: nil,
+			key:  "k",
+			want: 0,
+		},
+		{
+			// Key exists with no value, returns the zero value.
+			item: map[string]*dynamodb.AttributeValue{"k": {}},
+			key:  "k",
+			want: 0,
+		},
+		{
+			// Key exists, is negative. Returns the zero value.
+			item: map[string]*dynamodb.AttributeValue{"k": {N: aws.String("-1")}},
+			key:  "k",
+			want: 0,
+		},
+		{
+			// Key exists, is a large number.
+			item: map[string]*dynamodb.AttributeValue{"k": {N: aws.String("18446744073709551615")}},
+			key:  "k",
+			want: math.MaxUint64,
+		},
+	}
+	for i, test := range tests {
+		got := Uint64(test.item, test.key)
+		if got != test.want {
+			t.Errorf("%d Uint64() got %#v, want %#v", i, got, test.want)
+		}
+	}
+}
+
+func TestFloat64(t *testing.T) {
+	tests := []struct {
+		item map[string]*dynamodb.AttributeValue
+		key  string
+		want float64
+	}{
+		{
+			// Item is nil, returns the zero value.
+			item: nil,
+			key:  "k",
+			want: 0,
+		},
+		{
+			// Key exists with no value, returns the zero value.
+			item: map[string]*dynamodb.AttributeValue{"k": {}},
+			key:  "k",
+			want: 0,
+		},
+		{
+			// Key exists, fails to parse. Returns the zero value.
+			item: map[string]*dynamodb.AttributeValue{"k": {N: aws.String("abc")}},
+			key:  "k",
+			want: 0,
+		},
+		{
+			// Key exists, is a fraction.
+			item: map[string]*dynamodb.AttributeValue{"k": {N: aws.String("-1.25")}},
+			key:  "k",
+			want: -1.25,
+		},
+		{
+			// Key exists, uses exponent notation.
+			item: map[string]*dynamodb.AttributeValue{"k": {N: aws.String("1E+3")}},
+			key:  "k",
+			want: 1000,
+		},
+	}
+	for i, test := range tests {
+		got := Float64(test.item, test.key)
+		if got != test.want {
+			t.Errorf("%d Float64() got %#v, want %#v", i, got, test.want)
+		}
+	}
+}
+
+func TestBool(t *testing.T) {
+	tests := []struct {
+		item map[string]*dynamodb.AttributeValue
+		key  string
+		want bool
+	}{
+		{
+			// Item is nil, returns the zero value.
+			item: nil,
+			key:  "k",
+			want: false,
+		},
+		{
+			// Key exists with no value, returns the zero value.
+			item: map[string]*dynamodb.AttributeValue{"k": {}},
+			key:  "k",
+			want: false,
+		},
+		{
+			// Key exists with a different type, returns the zero value.
+			item: map[string]*dynamodb.AttributeValue{"k": {S: aws.String("true")}},
+			key:  "k",
+			want: false,
+		},
+		{
+			// Key exists, is true.
+			item: map[string]*dynamodb.AttributeValue{"k": {BOOL: aws.Bool(true)}},
+			key:  "k",
+			want: true,
+		},
+	}
+	for i, test := range tests {
+		got := Bool(test.item, test.key)
+		if got != test.want {
+			t.Errorf("%d Bool() got %#v, want %#v", i, got, test.want)
+		}
+	}
+}
+
+func TestBytes(t *testing.T) {
+	tests := []struct {
+		item map[string]*dynamodb.AttributeValue
+		key  string
+		want []byte
+	}{
+		{
+			// Item is nil, returns the zero value.
+			item: nil,
+			key:  "k",
+			want: nil,
+		},
+		{
+			// Key exists with no value, returns the zero value.
+			item: map[string]*dynamodb.AttributeValue{"k": {}},
+			key:  "k",
+			want: nil,
+		},
+		{
+			// Key and value exist, returns the value.
+			item: map[string]*dynamodb.AttributeValue{"k": {B: []byte("v")}},
+			key:  "k",
+			want: []byte("v"),
+		},
+	}
+	for i, test := range tests {
+		got := Bytes(test.item, test.key)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%d Bytes() got %#v, want %#v", i, got, test.want)
+		}
+	}
+}
+
+func TestSetInt64(t *testing.T) {
+	tests := []struct {
+		key  string
+		val  int64
+		want map[string]*dynamodb.AttributeValue
+	}{
+		{
+			key:  "",
+			val:  33,
+			want: map[string]*dynamodb.AttributeValue{},
+		},
+		{
+			key:  "k",
+			val:  math.MinInt64,
+			want: map[string]*dynamodb.AttributeValue{"k": {N: aws.String("-9223372036854775808")}},
+		},
+	}
+	for i, test := range tests {
+		item := map[string]*dynamodb.AttributeValue{}
+		SetInt64(item, test.key, test.val)
+		if !reflect.DeepEqual(item, test.want) {
+			t.Errorf("%d SetInt64() got %#v, want %#v", i, item, test.want)
+		}
+	}
+}
+
+func TestSetUint64(t *testing.T) {
+	tests := []struct {
+		key  string
+		val  uint64
+		want map[string]*dynamodb.AttributeValue
+	}{
+		{
+			key:  "",
+			val:  33,
+			want: map[string]*dynamodb.AttributeValue{},
+		},
+		{
+			key:  "k",
+			val:  math.MaxUint64,
+			want: map[string]*dynamodb.AttributeValue{"k": {N: aws.String("18446744073709551615")}},
+		},
+	}
+	for i, test := range tests {
+		item := map[string]*dynamodb.AttributeValue{}
+		SetUint64(item, test.key, test.val)
+		if !reflect.DeepEqual(item, test.want) {
+			t.Errorf("%d SetUint64() got %#v, want %#v", i, item, test.want)
+		}
+	}
+}
+
+func TestSetFloat64(t *testing.T) {
+	tests := []struct {
+		key  string
+		val  float64
+		want map[string]*dynamodb.AttributeValue
+	}{
+		{
+			key:  "k",
+			val:  0,
+			want: map[string]*dynamodb.AttributeValue{"k": {N: aws.String("0")}},
+		},
+		{
+			key:  "k",
+			val:  -1.25,
+			want: map[string]*dynamodb.AttributeValue{"k": {N: aws.String("-1.25")}},
+		},
+		{
+			key:  "k",
+			val:  math.NaN(),
+			want: map[string]*dynamodb.AttributeValue{},
+		},
+		{
+			key:  "k",
+			val:  math.Inf(1),
+			want: map[string]*dynamodb.AttributeValue{},
+		},
+		{
+			// Too small for DynamoDB, rounds to 0.
+			key:  "k",
+			val:  -1e-200,
+			want: map[string]*dynamodb.AttributeValue{"k": {N: aws.String("0")}},
+		},
+		{
+			// Too large for DynamoDB, skipped.
+			key:  "k",
+			val:  1e200,
+			want: map[string]*dynamodb.AttributeValue{},
+		},
+	}
+	for i, test := range tests {
+		item := map[string]*dynamodb.AttributeValue{}
+		SetFloat64(item, test.key, test.val)
+		if !reflect.DeepEqual(item, test.want) {
+			t.Errorf("%d SetFloat64() got %#v, want %#v", i, item, test.want)
+		}
+	}
+}
+
+func TestSetBool(t *testing.T) {
+	tests := []struct {
+		key  string
+		val  bool
+		want map[string]*dynamodb.AttributeValue
+	}{
+		{
+			key:  "",
+			val:  true,
+			want: map[string]*dynamodb.AttributeValue{},
+		},
+		{
+			key:  "k",
+			val:  false,
+			want: map[string]*dynamodb.AttributeValue{"k": {BOOL: aws.Bool(false)}},
+		},
+	}
+	for i, test := range tests {
+		item := map[string]*dynamodb.AttributeValue{}
+		SetBool(item, test.key, test.val)
+		if !reflect.DeepEqual(item, test.want) {
+			t.Errorf("%d SetBool() got %#v, want %#v", i, item, test.want)
+		}
+	}
+}
+
+func TestSetBytes(t *testing.T) {
+	tests := []struct {
+		key  string
+		val  []byte
+		want map[string]*dynamodb.AttributeValue
+	}{
+		{
+			key:  "k",
+			val:  []byte{},
+			want: map[string]*dynamodb.AttributeValue{},
+		},
+		{
+			key:  "",
+			val:  []byte("v"),
+			want: map[string]*dynamodb.AttributeValue{},
+		},
+		{
+			key:  "k",
+			val:  []byte("v"),
+			want: map[string]*dynamodb.AttributeValue{"k": {B: []byte("v")}},
+		},
+	}
+	for i, test := range tests {
+		item := map[string]*dynamodb.AttributeValue{}
+		SetBytes(item, test.key, test.val)
+		if !reflect.DeepEqual(item, test.want) {
+			t.Errorf("%d SetBytes() got %#v, want %#v", i, item, test.want)
+		}
+	}
+}
+
+func TestStrSet(t *testing.T) {
+	tests := []struct {
+		item map[string]*dynamodb.AttributeValue
+		key  string
+		want []string
+	}{
+		{
+			// Item is nil, returns the zero value.
+			item: nil,
+			key:  "k",
+			want: nil,
+		},
+		{
+			// Key exists with no value, returns the zero value.
+			item: map[string]*dynamodb.AttributeValue{"k": {}},
+			key:  "k",
+			want: nil,
+		},
+		{
+			// Members are sorted and de-duplicated.
+			item: map[string]*dynamodb.AttributeValue{"k": {SS: aws.StringSlice([]string{"b", "a", "b"})}},
+			key:  "k",
+			want: []string{"a", "b"},
+		},
+	}
+	for i, test := range tests {
+		got := StrSet(test.item, test.key)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%d StrSet() got %#v, want %#v", i, got, test.want)
+		}
+	}
+}
+
+func TestIntSet(t *testing.T) {
+	tests := []struct {
+		item map[string]*dynamodb.AttributeValue
+		key  string
+		want []int
+	}{
+		{
+			// Item is nil, returns the zero value.
+			item: nil,
+			key:  "k",
+			want: nil,
+		},
+		{
+			// Key exists with no value, returns the zero value.
+			item: map[string]*dynamodb.AttributeValue{"k": {}},
+			key:  "k",
+			want: nil,
+		},
+		{
+			// Members are sorted and de-duplicated, bad members are ignored.
+			item: map[string]*dynamodb.AttributeValue{"k": {NS: aws.StringSlice([]string{"3", "-1", "NaN", "3"})}},
+			key:  "k",
+			want: []int{-1, 3},
+		},
+	}
+	for i, test := range tests {
+		got := IntSet(test.item, test.key)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%d IntSet() got %#v, want %#v", i, got, test.want)
+		}
+	}
+}
+
+func TestBytesSet(t *testing.T) {
+	tests := []struct {
+		item map[string]*dynamodb.AttributeValue
+		key  string
+		want [][]byte
+	}{
+		{
+			// Item is nil, returns the zero value.
+			item: nil,
+			key:  "k",
+			want: nil,
+		},
+		{
+			// Key exists with no value, returns the zero value.
+			item: map[string]*dynamodb.AttributeValue{"k": {}},
+			key:  "k",
+			want: nil,
+		},
+		{
+			// Members are sorted and de-duplicated.
+			item: map[string]*dynamodb.AttributeValue{"k": {BS: [][]byte{[]byte("b"), []byte("a"), []byte("b")}}},
+			key:  "k",
+			want: [][]byte{[]byte("a"), []byte("b")},
+		},
+	}
+	for i, test := range tests {
+		got := BytesSet(test.item, test.key)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%d BytesSet() got %#v, want %#v", i, got, test.want)
+		}
+	}
+}
+
+func TestSetStrSet(t *testing.T) {
+	tests := []struct {
+		key  string
+		val  []string
+		want map[string]*dynamodb.AttributeValue
+	}{
+		{
+			key:  "k",
+			val:  nil,
+			want: map[string]*dynamodb.AttributeValue{},
+		},
+		{
+			key:  "k",
+			val:  []string{""},
+			want: map[string]*dynamodb.AttributeValue{},
+		},
+		{
+			key:  "",
+			val:  []string{"a"},
+			want: map[string]*dynamodb.AttributeValue{},
+		},
+		{
+			key:  "k",
+			val:  []string{"b", "", "a", "b"},
+			want: map[string]*dynamodb.AttributeValue{"k": {SS: aws.StringSlice([]string{"a", "b"})}},
+		},
+	}
+	for i, test := range tests {
+		item := map[string]*dynamodb.AttributeValue{}
+		SetStrSet(item, test.key, test.val)
+		if !reflect.DeepEqual(item, test.want) {
+			t.Errorf("%d SetStrSet() got %#v, want %#v", i, item, test.want)
+		}
+	}
+}
+
+func TestSetIntSet(t *testing.T) {
+	tests := []struct {
+		key  string
+		val  []int
+		want map[string]*dynamodb.AttributeValue
+	}{
+		{
+			key:  "k",
+			val:  []int{},
+			want: map[string]*dynamodb.AttributeValue{},
+		},
+		{
+			key:  "k",
+			val:  []int{3, 0, 3, -1},
+			want: map[string]*dynamodb.AttributeValue{"k": {NS: aws.StringSlice([]string{"-1", "0", "3"})}},
+		},
+	}
+	for i, test := range tests {
+		item := map[string]*dynamodb.AttributeValue{}
+		SetIntSet(item, test.key, test.val)
+		if !reflect.DeepEqual(item, test.want) {
+			t.Errorf("%d SetIntSet() got %#v, want %#v", i, item, test.want)
+		}
+	}
+}
+
+func TestSetBytesSet(t *testing.T) {
+	tests := []struct {
+		key  string
+		val  [][]byte
+		want map[string]*dynamodb.AttributeValue
+	}{
+		{
+			key:  "k",
+			val:  [][]byte{{}},
+			want: map[string]*dynamodb.AttributeValue{},
+		},
+		{
+			key:  "k",
+			val:  [][]byte{[]byte("b"), []byte("a"), []byte("b")},
+			want: map[string]*dynamodb.AttributeValue{"k": {BS: [][]byte{[]byte("a"), []byte("b")}}},
+		},
+	}
+	for i, test := range tests {
+		item := map[string]*dynamodb.AttributeValue{}
+		SetBytesSet(item, test.key, test.val)
+		if !reflect.DeepEqual(item, test.want) {
+			t.Errorf("%d SetBytesSet() got %#v, want %#v", i, item, test.want)
+		}
+	}
+}
+
+func TestMap(t *testing.T) {
+	nested := map[string]*dynamodb.AttributeValue{"k": {S: aws.String("v")}}
+	tests := []struct {
+		item map[string]*dynamodb.AttributeValue
+		key  string
+		want map[string]*dynamodb.AttributeValue
+	}{
+		{
+			// Item is nil, returns the zero value.
+			item: nil,
+			key:  "m",
+			want: nil,
+		},
+		{
+			// Key exists with a different type, returns the zero value.
+			item: map[string]*dynamodb.AttributeValue{"m": {S: aws.String("v")}},
+			key:  "m",
+			want: nil,
+		},
+		{
+			// Key and value exist, returns the value.
+			item: map[string]*dynamodb.AttributeValue{"m": {M: nested}},
+			key:  "m",
+			want: nested,
+		},
+	}
+	for i, test := range tests {
+		got := Map(test.item, test.key)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%d Map() got %#v, want %#v", i, got, test.want)
+		}
+	}
+}
+
+func TestList(t *testing.T) {
+	list := []*dynamodb.AttributeValue{{S: aws.String("v")}}
+	tests := []struct {
+		item map[string]*dynamodb.AttributeValue
+		key  string
+		want []*dynamodb.AttributeValue
+	}{
+		{
+			// Item is nil, returns the zero value.
+			item: nil,
+			key:  "l",
+			want: nil,
+		},
+		{
+			// Key exists with a different type, returns the zero value.
+			item: map[string]*dynamodb.AttributeValue{"l": {S: aws.String("v")}},
+			key:  "l",
+			want: nil,
+		},
+		{
+			// Key and value exist, returns the value.
+			item: map[string]*dynamodb.AttributeValue{"l": {L: list}},
+			key:  "l",
+			want: list,
+		},
+	}
+	for i, test := range tests {
+		got := List(test.item, test.key)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%d List() got %#v, want %#v", i, got, test.want)
+		}
+	}
+}
+
+func TestSetMap(t *testing.T) {
+	nested := map[string]*dynamodb.AttributeValue{"k": {S: aws.String("v")}}
+	tests := []struct {
+		key  string
+		val  map[string]*dynamodb.AttributeValue
+		want map[string]*dynamodb.AttributeValue
+	}{
+		{
+			key:  "m",
+			val:  map[string]*dynamodb.AttributeValue{},
+			want: map[string]*dynamodb.AttributeValue{},
+		},
+		{
+			key:  "",
+			val:  nested,
+			want: map[string]*dynamodb.AttributeValue{},
+		},
+		{
+			key:  "m",
+			val:  nested,
+			want: map[string]*dynamodb.AttributeValue{"m": {M: nested}},
+		},
+	}
+	for i, test := range tests {
+		item := map[string]*dynamodb.AttributeValue{}
+		SetMap(item, test.key, test.val)
+		if !reflect.DeepEqual(item, test.want) {
+			t.Errorf("%d SetMap() got %#v, want %#v", i, item, test.want)
+		}
+	}
+}
+
+func TestSetList(t *testing.T) {
+	v := &dynamodb.AttributeValue{S: aws.String("v")}
+	tests := []struct {
+		key  string
+		val  []*dynamodb.AttributeValue
+		want map[string]*dynamodb.AttributeValue
+	}{
+		{
+			key:  "l",
+			val:  []*dynamodb.AttributeValue{},
+			want: map[string]*dynamodb.AttributeValue{},
+		},
+		{
+			key:  "l",
+			val:  []*dynamodb.AttributeValue{nil},
+			want: map[string]*dynamodb.AttributeValue{},
+		},
+		{
+			key:  "l",
+			val:  []*dynamodb.AttributeValue{nil, v},
+			want: map[string]*dynamodb.AttributeValue{"l": {L: []*dynamodb.AttributeValue{v}}},
+		},
+	}
+	for i, test := range tests {
+		item := map[string]*dynamodb.AttributeValue{}
+		SetList(item, test.key, test.val)
+		if !reflect.DeepEqual(item, test.want) {
+			t.Errorf("%d SetList() got %#v, want %#v", i, item, test.want)
+		}
+	}
+}
+
+func TestNull(t *testing.T) {
+	item := map[string]*dynamodb.AttributeValue{
+		"s":     {S: aws.String("v")},
+		"nil":   nil,
+		"false": {NULL: aws.Bool(false)},
+	}
+	SetNull(item, "n")
+	SetNull(item, "")
+	tests := []struct {
+		key    string
+		has    bool
+		isNull bool
+	}{
+		{key: "s", has: true, isNull: false},
+		{key: "n", has: true, isNull: true},
+		{key: "false", has: true, isNull: false},
+		{key: "nil", has: false, isNull: false},
+		{key: "missing", has: false, isNull: false},
+		{key: "", has: false, isNull: false},
+	}
+	for i, test := range tests {
+		if got := Has(item, test.key); got != test.has {
+			t.Errorf("%d Has(%q) got %v, want %v", i, test.key, got, test.has)
+		}
+		if got := IsNull(item, test.key); got != test.isNull {
+			t.Errorf("%d IsNull(%q) got %v, want %v", i, test.key, got, test.isNull)
+		}
+	}
+	if got, want := item["n"], (&dynamodb.AttributeValue{NULL: aws.Bool(true)}); !reflect.DeepEqual(got, want) {
+		t.Errorf("SetNull() got %#v, want %#v", got, want)
+	}
+	if got := Str(item, "n"); got != "" {
+		t.Errorf("Str(null) got %#v", got)
+	}
+}
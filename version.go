@@ -0,0 +1,92 @@
+package dynamis
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Versioned writes implement optimistic locking with a number attribute that
+// counts the writes to an item. A write succeeds only if the stored version is
+// still the one that was read, and it stores the next version. An item with
+// no version attribute is at version 0, whether or not it exists, so a write
+// at version 0 creates a new item or takes over an existing item that was
+// written without versions. It fails only if a versioned write got there
+// first.
+
+// versionPath checks that versionKey names a top-level attribute, which is
+// where the version is read and written, and returns it as a path.
+func versionPath(versionKey string) ([]pathElem, error) {
+	elems, err := parsePath(versionKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(elems) != 1 {
+		return nil, fmt.Errorf("dynamis: version key %q is not a top-level attribute", versionKey)
+	}
+	return elems, nil
+}
+
+// versionCond matches items that are still at the version.
+func versionCond(versionKey string, version int) Condition {
+	if version == 0 {
+		return Cond.AttributeNotExists(versionKey)
+	}
+	return Cond.Eq(versionKey, version)
+}
+
+// versionErr turns a failed version condition into ErrVersionConflict.
+func versionErr(err error) error {
+	if err == ErrConditionFailed {
+		return ErrVersionConflict
+	}
+	return err
+}
+
+// PutVersioned writes the item built by the function if the stored item is
+// still at the version the function wrote to versionKey, which is read with
+// Int. The item is stored with the next version, which PutVersioned returns.
+// If the stored item has changed, it returns ErrVersionConflict. The version
+// must be a top-level attribute, so versionKey can't be a nested path.
+func (t Table) PutVersioned(versionKey string, item func(ValueWriter)) (int, error) {
+	if _, err := versionPath(versionKey); err != nil {
+		return 0, err
+	}
+	i, err := buildItem(item)
+	if err != nil {
+		return 0, err
+	}
+	version := Int(i, versionKey)
+	SetInt(i, versionKey, version+1)
+	if err := t.putIf(versionCond(versionKey, version), i); err != nil {
+		return 0, versionErr(err)
+	}
+	return version + 1, nil
+}
+
+// UpdateVersioned applies the update to the item with the key written by the
+// function if the stored item is still at the version in current, such as the
+// reader returned by Get. The update also sets the next version. It returns a
+// reader of the item after the update, or ErrVersionConflict if the stored
+// item has changed. As with PutVersioned, the version must be a top-level
+// attribute, and the update must not write it itself.
+func (t Table) UpdateVersioned(versionKey string, current ValueReader, key func(ValueWriter), u *UpdateBuilder) (ValueReader, error) {
+	if u == nil {
+		return NewValueReader(nil), errors.New("dynamis: update is nil")
+	}
+	vpath, err := versionPath(versionKey)
+	if err != nil {
+		return NewValueReader(nil), err
+	}
+	for _, a := range u.actions {
+		if elems, err := parsePath(a.path); err == nil && pathsOverlap(elems, vpath) {
+			return NewValueReader(nil), fmt.Errorf("dynamis: update writes %q, which holds the version", a.path)
+		}
+	}
+	version := current.Int(versionKey)
+	// Copy the update so the caller's builder doesn't get the version.
+	next := &UpdateBuilder{make([]updateAction, len(u.actions))}
+	copy(next.actions, u.actions)
+	next.Set(versionKey, version+1)
+	r, err := t.UpdateIf(versionCond(versionKey, version), key, next)
+	return r, versionErr(err)
+}
@@ -0,0 +1,104 @@
+package dynamis
+
+import "testing"
+
+func TestVersionCond(t *testing.T) {
+	tests := []struct {
+		version int
+		want    string
+	}{
+		{version: 0, want: "attribute_not_exists(#n0)"},
+		{version: 3, want: "#n0 = :v0"},
+	}
+	for i, test := range tests {
+		expr, names, _, err := versionCond("version", test.version).Build()
+		if err != nil || expr != test.want || *names["#n0"] != "version" {
+			t.Errorf("%d versionCond() got (%q, %v), want %q", i, expr, err, test.want)
+		}
+	}
+	if got := versionErr(ErrConditionFailed); got != ErrVersionConflict {
+		t.Errorf("versionErr() got %v, want %v", got, ErrVersionConflict)
+	}
+	if got := versionErr(nil); got != nil {
+		t.Errorf("versionErr(nil) got %v", got)
+	}
+}
+
+func TestVersionedErrors(t *testing.T) {
+	// The errors are found before anything is sent, so the table needs no
+	// client.
+	table := Table{tableName: "users"}
+	current := NewValueReader(nil)
+	key := func(w ValueWriter) { w.Str("id", "u1") }
+	tests := []*UpdateBuilder{
+		nil,
+		NewUpdateBuilder().Set("name", "a").Set("version", 5),
+		NewUpdateBuilder().Add("version", 1),
+		NewUpdateBuilder().Remove("version"),
+		NewUpdateBuilder().Set("version.n", 1),
+	}
+	for i, u := range tests {
+		if r, err := table.UpdateVersioned("version", current, key, u); err == nil || r == nil {
+			t.Errorf("%d UpdateVersioned() got (%#v, %v), want error", i, r, err)
+		}
+	}
+	// The version must be a top-level attribute.
+	for _, versionKey := range []string{"bad[x]", "meta.version", "versions[0]"} {
+		if _, err := table.UpdateVersioned(versionKey, current, key, NewUpdateBuilder().Set("a", 1)); err == nil {
+			t.Errorf("UpdateVersioned(%q) want error", versionKey)
+		}
+		if _, err := table.PutVersioned(versionKey, key); err == nil {
+			t.Errorf("PutVersioned(%q) want error", versionKey)
+		}
+	}
+}
+
+func TestTableVersioned(t *testing.T) {
+	tbl := newTable()
+	if err := createStrTable(tbl); err != nil {
+		t.Fatalf("Failed initializing: %s", err)
+	}
+	table := CheckTable(tbl.db, tbl.name)
+	key := func(w ValueWriter) {
+		w.Str("str", "k1")
+	}
+	put := func(version int, name string) (int, error) {
+		return table.PutVersioned("version", func(w ValueWriter) {
+			key(w)
+			w.Int("version", version)
+			w.Str("name", name)
+		})
+	}
+
+	// The first write creates the item at version 1.
+	if v, err := put(0, "a"); v != 1 || err != nil {
+		t.Fatalf("PutVersioned(0) got (%d, %v), want 1", v, err)
+	}
+	if v, err := put(0, "b"); v != 0 || err != ErrVersionConflict {
+		t.Errorf("PutVersioned(0) again got (%d, %v), want %v", v, err, ErrVersionConflict)
+	}
+	if v, err := put(1, "c"); v != 2 || err != nil {
+		t.Errorf("PutVersioned(1) got (%d, %v), want 2", v, err)
+	}
+
+	// Updates read the version from a reader.
+	r, err := table.Get(key)
+	if err != nil {
+		t.Fatalf("Get() error %s", err)
+	}
+	u := NewUpdateBuilder().Set("name", "d")
+	r2, err := table.UpdateVersioned("version", r, key, u)
+	if err != nil {
+		t.Fatalf("UpdateVersioned() error %s", err)
+	}
+	if r2.Int("version") != 3 || r2.Str("name") != "d" {
+		t.Errorf("UpdateVersioned() got version %d, name %q", r2.Int("version"), r2.Str("name"))
+	}
+	// The stale reader conflicts, and the caller's update is unchanged.
+	if _, err := table.UpdateVersioned("version", r, key, u); err != ErrVersionConflict {
+		t.Errorf("UpdateVersioned(stale) got %v, want %v", err, ErrVersionConflict)
+	}
+	if expr, _, _, _ := u.Build(); expr != "SET #n0 = :v0" {
+		t.Errorf("UpdateVersioned() changed the update to %q", expr)
+	}
+}